@@ -34,6 +34,12 @@ do
     curl -k -v "$ADDRESS/report/${cluster}" > "localhost/report_${cluster}.json"
 done
 
+# clusters that do not belong to given organization are not found
+for cluster in $clusters
+do
+    echo "${cluster} $(curl -k -s -o /dev/null -w '%{http_code}' "$ADDRESS/report/1/${cluster}")"
+done > localhost/report_other_org_status.txt
+
 RED_BG=$(tput setab 1)
 GREEN_BG=$(tput setab 2)
 NC=$(tput sgr0) # No Color
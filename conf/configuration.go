@@ -43,6 +43,7 @@ import (
 	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/groups"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
 )
 
 const (
@@ -57,10 +58,11 @@ type PathsConfiguration struct {
 
 // ConfigStruct is a structure holding the whole service configuration
 type ConfigStruct struct {
-	Server  server.Configuration  `mapstructure:"server" toml:"server"`
-	Content content.Configuration `mapstructure:"content" toml:"content"`
-	Groups  groups.Configuration  `mapstructure:"groups" toml:"groups"`
-	Paths   PathsConfiguration    `mapstructure:"paths" toml:"paths"`
+	Server   server.Configuration          `mapstructure:"server" toml:"server"`
+	Content  content.Configuration         `mapstructure:"content" toml:"content"`
+	Groups   groups.Configuration          `mapstructure:"groups" toml:"groups"`
+	Paths    PathsConfiguration            `mapstructure:"paths" toml:"paths"`
+	Requests storage.RequestsConfiguration `mapstructure:"requests" toml:"requests"`
 }
 
 // Config has exactly the same structure as *.toml file
@@ -149,6 +151,11 @@ func GetContentConfiguration() content.Configuration {
 	return Config.Content
 }
 
+// GetRequestsConfiguration returns configuration of request IDs lifecycle
+func GetRequestsConfiguration() storage.RequestsConfiguration {
+	return Config.Requests
+}
+
 // checkIfFileExists returns nil if path doesn't exist or isn't a file,
 // otherwise it returns corresponding error
 func checkIfFileExists(path string) error {
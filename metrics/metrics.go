@@ -35,28 +35,24 @@ limitations under the License.
 package metrics
 
 import (
+	utilsmetrics "github.com/RedHatInsights/insights-operator-utils/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// API metrics are registered by insights-operator-utils package already
+// (it is imported by HTTP utilities used by this service), so the very same
+// collectors need to be used to avoid duplicate registration.
+
 // APIRequests is a counter vector for requests to endpoints
-var APIRequests = promauto.NewCounterVec(prometheus.CounterOpts{
-	Name: "api_endpoints_requests",
-	Help: "The total number of requests per endpoint",
-}, []string{"endpoint"})
+var APIRequests = utilsmetrics.APIRequests
 
 // APIResponsesTime collects the information about api response time per endpoint
-var APIResponsesTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
-	Name:    "api_endpoints_response_time",
-	Help:    "API endpoints response time",
-	Buckets: prometheus.LinearBuckets(0, 20, 20),
-}, []string{"endpoint"})
-
-// APIResponseStatusCodes collects the information about api response status codes
-var APIResponseStatusCodes = promauto.NewCounterVec(prometheus.CounterOpts{
-	Name: "api_endpoints_status_codes",
-	Help: "API endpoints status codes",
-}, []string{"status_code"})
+var APIResponsesTime = utilsmetrics.APIResponsesTime
+
+// APIResponseStatusCodes collects the information about api response status
+// codes per endpoint
+var APIResponseStatusCodes = utilsmetrics.APIResponseStatusCodes
 
 // ConsumedMessages shows number of messages consumed from Kafka by aggregator
 var ConsumedMessages = promauto.NewCounter(prometheus.CounterOpts{
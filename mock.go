@@ -19,9 +19,12 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
+	"math"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -30,6 +33,7 @@ import (
 	"github.com/RedHatInsights/insights-results-aggregator-mock/groups"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
 )
 
 const (
@@ -90,9 +94,31 @@ func startService(config *conf.ConfigStruct) int {
 		log.Error().Err(err).Msg("Storage initialization error")
 		return ExitStatusServerError
 	}
+	storageInstance.SetRequestsConfiguration(conf.GetRequestsConfiguration())
 
 	serverInstance = server.New(serverCfg, storageInstance, ruleGroups, ruleContent)
 
+	// groups and rule content are read from the same files on reload
+	serverInstance.Loader = func() (map[string]groups.Group, []content.RuleContent, error) {
+		ruleGroups, err := groups.ParseGroupConfigFile(groupsCfg.ConfigPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		ruleContent, err := content.ParseContent(contentCfg.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ruleGroups, ruleContent, nil
+	}
+
+	if serverCfg.WatchFiles {
+		err = serverInstance.WatchFiles(config.Paths.MockDataPath, groupsCfg.ConfigPath, contentCfg.Path)
+		if err != nil {
+			log.Error().Err(err).Msg("File watcher initialization error")
+			return ExitStatusServerError
+		}
+	}
+
 	err = serverInstance.Start()
 	if err != nil {
 		log.Error().Err(err).Msg("HTTP(s) start error")
@@ -140,6 +166,14 @@ The commands are:
     config   print-config        prints current configuration set by files & env variables
     version  print-version-info  prints version info
     authors  print-authors       prints authors
+    mint-token                   prints JWT token signed by key from configuration
+
+Flags of mint-token command:
+
+    -org      organization ID (default 11789772)
+    -account  account number
+    -user     user ID
+    -validity token validity, zero for tokens that never expire (default 24h)
 
 `
 
@@ -172,6 +206,46 @@ func printConfig(config *conf.ConfigStruct) int {
 	return ExitStatusOK
 }
 
+// mintToken prints JWT token that can be used to access the service when
+// JWT authentication is enabled
+func mintToken(config *conf.ConfigStruct, args []string) int {
+	flags := flag.NewFlagSet("mint-token", flag.ContinueOnError)
+	orgID := flags.Uint("org", 11789772, "organization ID")
+	account := flags.String("account", "", "account number")
+	user := flags.String("user", "", "user ID")
+	validity := flags.Duration("validity", 24*time.Hour, "token validity")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return ExitStatusOther
+	}
+
+	if *orgID == 0 || *orgID > math.MaxUint32 {
+		log.Error().Uint("org", *orgID).Msg("Organization ID is out of range")
+		return ExitStatusOther
+	}
+
+	key, err := server.ReadJWTKey(config.Server.JWTKeyFile)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to read JWT key")
+		return ExitStatusOther
+	}
+
+	token, err := server.MintToken(key, types.Identity{
+		AccountNumber: *account,
+		OrgID:         types.OrgID(*orgID),
+		UserID:        types.UserID(*user),
+	}, *validity)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to mint JWT token")
+		return ExitStatusOther
+	}
+
+	fmt.Println(token)
+
+	return ExitStatusOK
+}
+
 func main() {
 	config, err := conf.LoadConfiguration(defaultConfigFilename)
 	if err != nil {
@@ -184,7 +258,7 @@ func main() {
 		command = strings.ToLower(strings.TrimSpace(os.Args[1]))
 	}
 
-	os.Exit(handleCommand(&config, removeDashes(command)))
+	os.Exit(handleCommand(&config, removeDashes(command), os.Args[min(len(os.Args), 2):]))
 }
 
 // function removeDashes removes one or two dashes from the beginning of a
@@ -195,7 +269,7 @@ func removeDashes(command string) string {
 	return command
 }
 
-func handleCommand(config *conf.ConfigStruct, command string) int {
+func handleCommand(config *conf.ConfigStruct, command string, args []string) int {
 	switch command {
 	case "start-service":
 		logVersionInfo()
@@ -213,6 +287,8 @@ func handleCommand(config *conf.ConfigStruct, command string) int {
 		return printVersionInfo()
 	case "authors", "print-authors":
 		return printAuthors()
+	case "mint-token":
+		return mintToken(config, args)
 	default:
 		fmt.Printf("\nCommand '%v' not found\n", command)
 		return printHelp()
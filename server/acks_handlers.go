@@ -63,25 +63,33 @@ const (
 //	  ]
 //	}
 //
-// Please note that for the sake of simplicity we don't use links section as
-// pagination is not supported ATM.
-func (server *HTTPServer) readAckList(writer http.ResponseWriter, _ *http.Request) {
+// The list can be paginated by limit and offset query parameters, filtered
+// by rule_id prefix and created_by, and sorted by created_at or updated_at
+// (use -created_at or -updated_at for descending order). Count stored in
+// metadata is the number of all acks that match filters.
+func (server *HTTPServer) readAckList(writer http.ResponseWriter, request *http.Request) {
+	parameters, err := readAckListParameters(request.URL.Query())
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
 	// set the response header
 	writer.Header().Set(contentType, appJSON)
 
+	acks := filterAcks(server.Storage.ListOfAcks(readAckOrgID(request)), parameters)
+	sortAcks(acks, parameters)
+
 	var responseBody types.AcknowledgementsResponse
 
 	// fill-in metadata part of response body
 	responseBody.Metadata.Count = len(acks)
 
-	// fill-in data part of response body
-	responseBody.Data = make([]types.Acknowledge, len(acks))
+	// fill-in links part of response body
+	responseBody.Links = ackListLinks(request.URL, parameters, len(acks))
 
-	i := 0
-	for _, ack := range acks {
-		responseBody.Data[i] = ack
-		i++
-	}
+	// fill-in data part of response body
+	responseBody.Data = paginateAcks(acks, parameters)
 
 	// serialize the above data structure into JSON format
 	bytes, err := json.MarshalIndent(responseBody, "", "\t")
@@ -146,17 +154,15 @@ func (server *HTTPServer) acknowledgePost(writer http.ResponseWriter, request *h
 		return
 	}
 
-	// try to find the rule in map of active rules
-	_, found := acks[parameters.RuleSelector]
-	if !found {
-		// rule not found -> add a new one
-		addNewRule(parameters.RuleSelector, parameters.Value, defaultUserName)
+	// try to add a new rule, existing rule is returned otherwise
+	ack, created := server.Storage.AddAck(readAckOrgID(request),
+		parameters.RuleSelector, parameters.Value, string(readUserID(request)))
+	if created {
 		// update HTTP status code accordingly
 		writer.WriteHeader(http.StatusCreated)
 	}
 
 	// return existing rule or the new one (if created)
-	ack := acks[parameters.RuleSelector]
 	returnRuleAckToClient(writer, &ack)
 }
 
@@ -190,17 +196,19 @@ func (server *HTTPServer) getAcknowledge(writer http.ResponseWriter, request *ht
 		return
 	}
 
-	// try to find the rule in map of active rules
-	_, found := acks[ruleSelector]
-	if !found {
-		// rule not found -> add a new one
-		addNewRule(ruleSelector, defaultJustification, defaultUserName)
-	} else {
+	// try to add a new rule
+	orgID := readAckOrgID(request)
+	ack, created := server.Storage.AddAck(orgID, ruleSelector, defaultJustification, string(readUserID(request)))
+	if !created {
 		// rule has been found -> just update it
-		updateRuleUpdatedAt(ruleSelector)
+		ack, err = server.Storage.UpdateAck(orgID, ruleSelector, nil)
+		if err != nil {
+			// rule has been deleted in the meantime
+			handleMissingRule(writer, string(ruleSelector))
+			return
+		}
 	}
 
-	ack := acks[ruleSelector]
 	returnRuleAckToClient(writer, &ack)
 }
 
@@ -239,8 +247,9 @@ func (server *HTTPServer) updateAcknowledge(writer http.ResponseWriter, request
 		return
 	}
 
-	// try to find the rule in map of active rules
-	_, found := acks[ruleSelector]
+	// try to find the rule in storage of active rules
+	orgID := readAckOrgID(request)
+	_, found := server.Storage.GetAck(orgID, ruleSelector)
 	if !found {
 		handleMissingRule(writer, string(ruleSelector))
 		// everything has been handled already
@@ -263,9 +272,13 @@ func (server *HTTPServer) updateAcknowledge(writer http.ResponseWriter, request
 		Msg("Justification provided")
 
 	// update existing rule
-	updateRuleJustification(ruleSelector, justification)
+	ack, err := server.Storage.UpdateAck(orgID, ruleSelector, &justification.Value)
+	if err != nil {
+		// rule has been deleted in the meantime
+		handleMissingRule(writer, string(ruleSelector))
+		return
+	}
 
-	ack := acks[ruleSelector]
 	returnRuleAckToClient(writer, &ack)
 }
 
@@ -283,17 +296,14 @@ func (server *HTTPServer) deleteAcknowledge(writer http.ResponseWriter, request
 		return
 	}
 
-	// try to find the rule in map of active rules
-	_, found := acks[ruleSelector]
-	if !found {
+	// try to delete the rule from storage of active rules
+	err = server.Storage.DeleteAck(readAckOrgID(request), ruleSelector)
+	if err != nil {
 		// return 404
 		writer.WriteHeader(http.StatusNotFound)
 		return
 	}
 
-	// rule has been acknowledget -> we can delete it
-	delete(acks, ruleSelector)
-
 	// return 204 -> rule ack has been deleted
 	writer.WriteHeader(http.StatusNoContent)
 }
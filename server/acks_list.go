@@ -0,0 +1,213 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// Pagination, filtering and sorting of list of acks. Query parameters and
+// links section are compatible with RHEL Insights Advisor.
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// Query parameters accepted by ack list endpoint
+const (
+	LimitParam     = "limit"
+	OffsetParam    = "offset"
+	RuleIDParam    = "rule_id"
+	CreatedByParam = "created_by"
+	SortParam      = "sort"
+)
+
+// Attributes that can be used to sort acks. Descending order is selected by
+// minus sign before attribute name, for example -created_at.
+const (
+	sortByCreatedAt = "created_at"
+	sortByUpdatedAt = "updated_at"
+)
+
+// ackListParameters contains all parameters of ack list request. Zero limit
+// means that all acks are returned when limit is not specified.
+type ackListParameters struct {
+	limit      int
+	offset     int
+	rulePrefix string
+	createdBy  string
+	sortBy     string
+	descending bool
+}
+
+// readNonNegativeParam reads optional numeric query parameter
+func readNonNegativeParam(query url.Values, name string, defaultValue int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("parameter '%s' must be a non-negative integer", name)
+	}
+	return number, nil
+}
+
+// readAckListParameters reads and validates all parameters of ack list
+// request
+func readAckListParameters(query url.Values) (ackListParameters, error) {
+	var parameters ackListParameters
+	var err error
+
+	parameters.limit, err = readNonNegativeParam(query, LimitParam, 0)
+	if err != nil {
+		return parameters, err
+	}
+	if query.Has(LimitParam) && parameters.limit == 0 {
+		return parameters, fmt.Errorf("parameter '%s' must be greater than zero", LimitParam)
+	}
+
+	parameters.offset, err = readNonNegativeParam(query, OffsetParam, 0)
+	if err != nil {
+		return parameters, err
+	}
+
+	parameters.rulePrefix = query.Get(RuleIDParam)
+	parameters.createdBy = query.Get(CreatedByParam)
+
+	sortBy := query.Get(SortParam)
+	parameters.descending = strings.HasPrefix(sortBy, "-")
+	parameters.sortBy = strings.TrimPrefix(sortBy, "-")
+	switch parameters.sortBy {
+	case "", sortByCreatedAt, sortByUpdatedAt:
+	default:
+		return parameters, fmt.Errorf("acks can not be sorted by '%s'", sortBy)
+	}
+
+	return parameters, nil
+}
+
+// filterAcks returns acks with rule selector starting with given prefix and
+// created by given user
+func filterAcks(acks []types.Acknowledge, parameters ackListParameters) []types.Acknowledge {
+	filtered := make([]types.Acknowledge, 0, len(acks))
+	for _, ack := range acks {
+		if !strings.HasPrefix(ack.Rule, parameters.rulePrefix) {
+			continue
+		}
+		if parameters.createdBy != "" && ack.CreatedBy != parameters.createdBy {
+			continue
+		}
+		filtered = append(filtered, ack)
+	}
+	return filtered
+}
+
+// parseAckTimestamp parses timestamp stored in ack. Timestamps that can not
+// be parsed are sorted before all other ones.
+func parseAckTimestamp(timestamp string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+// sortAcks sorts acks by selected timestamp. Order of acks with the same
+// timestamp is kept.
+func sortAcks(acks []types.Acknowledge, parameters ackListParameters) {
+	if parameters.sortBy == "" {
+		return
+	}
+
+	timestamp := func(ack types.Acknowledge) time.Time {
+		if parameters.sortBy == sortByUpdatedAt {
+			return parseAckTimestamp(ack.UpdatedAt)
+		}
+		return parseAckTimestamp(ack.CreatedAt)
+	}
+
+	sort.SliceStable(acks, func(i, j int) bool {
+		if parameters.descending {
+			return timestamp(acks[i]).After(timestamp(acks[j]))
+		}
+		return timestamp(acks[i]).Before(timestamp(acks[j]))
+	})
+}
+
+// paginateAcks returns acks on page selected by limit and offset
+func paginateAcks(acks []types.Acknowledge, parameters ackListParameters) []types.Acknowledge {
+	if parameters.offset >= len(acks) {
+		return []types.Acknowledge{}
+	}
+	if parameters.limit == 0 {
+		return acks[parameters.offset:]
+	}
+	end := min(parameters.offset+parameters.limit, len(acks))
+	return acks[parameters.offset:end]
+}
+
+// pageLink constructs link to page with given offset. All other query
+// parameters are kept. Zero limit is not added to the link.
+func pageLink(requestURL *url.URL, limit, offset int) string {
+	query := requestURL.Query()
+	if limit > 0 {
+		query.Set(LimitParam, strconv.Itoa(limit))
+	}
+	query.Set(OffsetParam, strconv.Itoa(offset))
+	return requestURL.Path + "?" + query.Encode()
+}
+
+// ackListLinks constructs links to first, previous, next, and last page of
+// list of acks with given number of items. Without limit, all acks are on
+// the first page.
+func ackListLinks(requestURL *url.URL, parameters ackListParameters, total int) types.AcknowledgementsLinks {
+	limit := parameters.limit
+	offset := parameters.offset
+
+	if limit == 0 {
+		first := pageLink(requestURL, 0, 0)
+		links := types.AcknowledgementsLinks{First: first, Last: first}
+		if offset > 0 {
+			links.Previous = &first
+		}
+		return links
+	}
+
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = (total - 1) / limit * limit
+	}
+
+	links := types.AcknowledgementsLinks{
+		First: pageLink(requestURL, limit, 0),
+		Last:  pageLink(requestURL, limit, lastOffset),
+	}
+	if offset > 0 {
+		previous := pageLink(requestURL, limit, max(min(offset-limit, lastOffset), 0))
+		links.Previous = &previous
+	}
+	if offset+limit < total {
+		next := pageLink(requestURL, limit, offset+limit)
+		links.Next = &next
+	}
+	return links
+}
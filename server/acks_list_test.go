@@ -0,0 +1,90 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// readAckList sends request to ack list endpoint and decodes its response
+func readAckList(t *testing.T, handler http.Handler, query string) (int, types.AcknowledgementsResponse) {
+	var response types.AcknowledgementsResponse
+
+	request := httptest.NewRequest(http.MethodGet, "/api/ack?"+query, http.NoBody)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code == http.StatusOK {
+		assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	}
+	return recorder.Code, response
+}
+
+// TestAckListPagination checks pagination, filtering, and sorting of acks
+func TestAckListPagination(t *testing.T) {
+	handler, storageInstance := newTestHandler(t, server.Configuration{APIPrefix: "/api/"}, nil)
+
+	// five acks are stored in fixture file
+	code, response := readAckList(t, handler, "limit=2&offset=2")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 5, response.Metadata.Count)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "/api/ack?limit=2&offset=0", response.Links.First)
+	assert.Equal(t, "/api/ack?limit=2&offset=0", *response.Links.Previous)
+	assert.Equal(t, "/api/ack?limit=2&offset=4", *response.Links.Next)
+	assert.Equal(t, "/api/ack?limit=2&offset=4", response.Links.Last)
+
+	// the last page
+	_, response = readAckList(t, handler, "limit=2&offset=4")
+	assert.Len(t, response.Data, 1)
+	assert.Nil(t, response.Links.Next)
+
+	// filters are kept in links
+	_, response = readAckList(t, handler, "rule_id=ccx_rules_ocp.external.rules.&created_by=tester3")
+	assert.Equal(t, 1, response.Metadata.Count)
+	assert.Equal(t, "tester3", response.Data[0].CreatedBy)
+	assert.Nil(t, response.Links.Previous)
+	assert.Equal(t, "/api/ack?created_by=tester3&offset=0&rule_id=ccx_rules_ocp.external.rules.",
+		response.Links.First)
+
+	// sorting by timestamp
+	justification := "updated"
+	_, err := storageInstance.UpdateAck(11789772, types.RuleSelector(response.Data[0].Rule), &justification)
+	assert.NoError(t, err)
+	_, response = readAckList(t, handler, "sort=-updated_at")
+	assert.Equal(t, "tester3", response.Data[0].CreatedBy)
+
+	// all acks are returned without limit
+	_, response = readAckList(t, handler, "")
+	assert.Len(t, response.Data, 5)
+	assert.Equal(t, "/api/ack?offset=0", response.Links.Last)
+	assert.Nil(t, response.Links.Next)
+
+	// improper parameters
+	for _, query := range []string{"limit=0", "limit=foo", "offset=-1", "sort=rule"} {
+		code, _ = readAckList(t, handler, query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+}
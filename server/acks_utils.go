@@ -20,14 +20,13 @@ package server
 // file acks_handlers.go.
 
 import (
-	"errors"
-	"time"
-
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
 )
 
@@ -36,11 +35,6 @@ const (
 	unableToReadRuleSelector = "Unable to read rule selector"
 )
 
-// formattedNow function returns current time formatted according to RFC3339
-func formattedNow() string {
-	return time.Now().Format(time.RFC3339)
-}
-
 // handleImproperSelector function handles situation when rule selector can not
 // be read from client request. HTTP code 400 Bad Request is returned in this
 // situation.
@@ -54,51 +48,17 @@ func handleImproperSelector(writer http.ResponseWriter, err error) {
 // be found in internal data structure.  HTTP code 404 Not Found is returned in
 // this situation.
 func handleMissingRule(writer http.ResponseWriter, _ string) {
-	err := errors.New("rule not found -> justification can not be changed")
+	err := storage.ErrAckNotFound
 	log.Error().Err(err).Msg("")
 	// return 404
 	http.Error(writer, err.Error(), http.StatusNotFound)
 }
 
-// addNewRule function add a new rule to existing map of acknowledges.
-func addNewRule(ruleSelector types.RuleSelector, justification, createdBy string) {
-	// add new rule
-	acks[ruleSelector] = types.Acknowledge{
-		Acknowledged:  true,
-		Rule:          string(ruleSelector),
-		Justification: justification,
-		CreatedBy:     createdBy,
-		CreatedAt:     formattedNow(),
-		UpdatedAt:     formattedNow(),
-	}
-}
-
-// updateRuleJustification function updates justification of given rule. It
-// also changes UpdatedAt attribute.
-func updateRuleJustification(ruleSelector types.RuleSelector, justification types.AcknowledgementJustification) {
-	// (it is impossible to change the struct in a map directly!)
-	ack := acks[ruleSelector]
-
-	// TODO: ask if that attribute needs to be updated as well
-	// ack.CreatedBy = defaultUserName
-	ack.UpdatedAt = formattedNow()
-	ack.Justification = justification.Value
-
-	// update map
-	acks[ruleSelector] = ack
-}
-
-// updateRuleUpdatedAt function just UpdatedAt attribute.
-func updateRuleUpdatedAt(ruleSelector types.RuleSelector) {
-	// (it is impossible to change the struct in a map directly!)
-	ack := acks[ruleSelector]
-
-	// TODO: ask if that attribute needs to be updated as well
-	// ack.CreatedBy = defaultUserName
-	ack.UpdatedAt = formattedNow()
-
-	// update map
-	acks[ruleSelector] = ack
+// readAckOrgID returns ID of organization where acks are made. It is the
+// organization stored in identity of user that made the request.
+func readAckOrgID(request *http.Request) types.OrgID {
+	identity, _ := readIdentity(request)
+	return identity.OrgID
 }
 
 // returnRuleAckToClient returns information about selected rule ack to client.
@@ -117,3 +77,30 @@ func returnRuleAckToClient(writer http.ResponseWriter, ack *types.Acknowledge) {
 		log.Error().Err(err).Msg(responseDataError)
 	}
 }
+
+// ackedRuleKey returns key that identifies rule in acks and in rule hits.
+// Rule selectors used by acks contain ".report" suffix that is not part of
+// rule ID stored in rule hits.
+func ackedRuleKey(ruleID, errorKey string) string {
+	return strings.TrimSuffix(ruleID, ".report") + "|" + errorKey
+}
+
+// ackedRulesForCluster returns keys of all rules acked in organization that
+// owns given cluster. Acks are not applied to clusters not assigned to any
+// organization.
+func (server *HTTPServer) ackedRulesForCluster(clusterName types.ClusterName) map[string]struct{} {
+	ackedRules := make(map[string]struct{})
+
+	orgID, err := server.Storage.GetOrgForCluster(clusterName)
+	if err != nil {
+		return ackedRules
+	}
+
+	for _, ack := range server.Storage.ListOfAcks(orgID) {
+		ruleID, errorKey, found := strings.Cut(ack.Rule, "|")
+		if found {
+			ackedRules[ackedRuleKey(ruleID, errorKey)] = struct{}{}
+		}
+	}
+	return ackedRules
+}
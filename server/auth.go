@@ -0,0 +1,248 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// Identity of user that made the request. Identity is read from
+// x-rh-identity header (the same header is set by 3scale gateway for real
+// services) or from JWT bearer token and it is stored in request context, so
+// all handlers can use it.
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/RedHatInsights/insights-operator-utils/responses"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// Supported authentication types
+const (
+	// AuthTypeNone disables authentication, default identity is used for
+	// all requests
+	AuthTypeNone = "none"
+
+	// AuthTypeXRH enables reading identity from x-rh-identity header
+	AuthTypeXRH = "xrh"
+
+	// AuthTypeJWT enables reading identity from JWT bearer token
+	AuthTypeJWT = "jwt"
+)
+
+// IdentityHeader is name of HTTP header that contains identity encoded by
+// base64
+const IdentityHeader = "x-rh-identity"
+
+// defaultOrgID is organization ID used when authentication is disabled
+const defaultOrgID = 11789772
+
+// contextKey is type for keys of values stored in request context
+type contextKey string
+
+// contextKeyIdentity is key of identity stored in request context
+const contextKeyIdentity contextKey = "identity"
+
+// xrhIdentity represents content of x-rh-identity header
+type xrhIdentity struct {
+	Identity struct {
+		AccountNumber string `json:"account_number"`
+		OrgID         string `json:"org_id"`
+		Internal      struct {
+			OrgID string `json:"org_id"`
+		} `json:"internal"`
+		User struct {
+			UserID   string `json:"user_id"`
+			Username string `json:"username"`
+		} `json:"user"`
+	} `json:"identity"`
+}
+
+// parseOrgID parses organization ID stored in identity
+func parseOrgID(value string) (types.OrgID, error) {
+	orgID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || orgID == 0 {
+		return 0, fmt.Errorf("invalid organization ID: '%s'", value)
+	}
+	return types.OrgID(orgID), nil
+}
+
+// decodeIdentityHeader decodes identity from x-rh-identity header value
+func decodeIdentityHeader(header string) (types.Identity, error) {
+	var identity types.Identity
+
+	decoded, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return identity, fmt.Errorf("identity is not encoded properly: %w", err)
+	}
+
+	var parsed xrhIdentity
+	err = json.Unmarshal(decoded, &parsed)
+	if err != nil {
+		return identity, fmt.Errorf("identity has improper format: %w", err)
+	}
+
+	orgID := parsed.Identity.OrgID
+	if orgID == "" {
+		orgID = parsed.Identity.Internal.OrgID
+	}
+	if orgID == "" {
+		return identity, errors.New("organization ID is not set in identity")
+	}
+
+	identity.OrgID, err = parseOrgID(orgID)
+	if err != nil {
+		return identity, err
+	}
+
+	identity.AccountNumber = parsed.Identity.AccountNumber
+	identity.UserID = types.UserID(parsed.Identity.User.UserID)
+	if identity.UserID == "" {
+		identity.UserID = types.UserID(parsed.Identity.User.Username)
+	}
+
+	return identity, nil
+}
+
+// errMissingIdentity is returned when request does not contain any
+// credentials or the credentials can not be verified
+var errMissingIdentity = errors.New("missing or invalid credentials")
+
+// readXRHIdentity reads identity from x-rh-identity header
+func readXRHIdentity(request *http.Request) (types.Identity, error) {
+	header := request.Header.Get(IdentityHeader)
+	if header == "" {
+		return types.Identity{}, fmt.Errorf("%w: missing %s header", errMissingIdentity, IdentityHeader)
+	}
+	return decodeIdentityHeader(header)
+}
+
+// authenticate is middleware that reads identity from x-rh-identity header
+// or from JWT token and stores it into request context. Requests without
+// valid credentials are rejected with 401 Unauthorized, requests with
+// malformed identity with 403 Forbidden. Public endpoints and CORS preflight
+// requests can be accessed without identity.
+func (server *HTTPServer) authenticate(nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodOptions || server.isPublicEndpoint(request) {
+			nextHandler.ServeHTTP(writer, request)
+			return
+		}
+
+		var identity types.Identity
+		var err error
+		if server.Config.AuthType == AuthTypeJWT {
+			identity, err = server.readJWTIdentity(request)
+		} else {
+			identity, err = readXRHIdentity(request)
+		}
+
+		if errors.Is(err, errMissingIdentity) {
+			log.Error().Err(err).Str("URI", request.RequestURI).Msg("Missing identity")
+			err = responses.SendUnauthorized(writer, err.Error())
+			if err != nil {
+				log.Error().Err(err).Msg(responseDataError)
+			}
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("URI", request.RequestURI).Msg("Malformed identity")
+			err = responses.SendForbidden(writer, err.Error())
+			if err != nil {
+				log.Error().Err(err).Msg(responseDataError)
+			}
+			return
+		}
+
+		ctx := context.WithValue(request.Context(), contextKeyIdentity, identity)
+		nextHandler.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// endpointTemplate returns route template for given request
+func endpointTemplate(request *http.Request) string {
+	route := mux.CurrentRoute(request)
+	if route == nil {
+		return ""
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	return template
+}
+
+// isPublicEndpoint checks if the endpoint can be accessed without identity
+func (server *HTTPServer) isPublicEndpoint(request *http.Request) bool {
+	apiPrefix := server.apiPrefix()
+	switch endpointTemplate(request) {
+	case apiPrefix + MainEndpoint, apiPrefix + MetricsEndpoint, server.openAPIURL():
+		return true
+	default:
+		return false
+	}
+}
+
+// readIdentity retrieves identity stored in request context. Default
+// identity is returned when authentication is disabled.
+func readIdentity(request *http.Request) (types.Identity, bool) {
+	identity, found := request.Context().Value(contextKeyIdentity).(types.Identity)
+	if !found {
+		return types.Identity{
+			OrgID:  defaultOrgID,
+			UserID: defaultUserName,
+		}, false
+	}
+	return identity, true
+}
+
+// checkAuthType checks if authentication type set in configuration is
+// supported
+func checkAuthType(authType string) error {
+	switch authType {
+	case "", AuthTypeNone, AuthTypeXRH, AuthTypeJWT:
+		return nil
+	default:
+		return fmt.Errorf("unsupported authentication type '%s'", authType)
+	}
+}
+
+// checkOrgPermissions checks if user that made the request can access given
+// organization. Forbidden response is sent otherwise. All organizations can
+// be accessed when authentication is disabled.
+func checkOrgPermissions(writer http.ResponseWriter, request *http.Request, orgID types.OrgID) bool {
+	identity, authenticated := readIdentity(request)
+	if !authenticated || identity.OrgID == orgID {
+		return true
+	}
+
+	message := fmt.Sprintf("you have no permissions to get or change info about the organization "+
+		"with ID %d; you can access info about organization with ID %d", orgID, identity.OrgID)
+	log.Error().Msg(message)
+	err := responses.SendForbidden(writer, message)
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+	return false
+}
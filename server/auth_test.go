@@ -0,0 +1,83 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// encodeIdentity encodes identity the same way as 3scale gateway does
+func encodeIdentity(identity string) string {
+	return base64.StdEncoding.EncodeToString([]byte(identity))
+}
+
+// TestDecodeIdentityHeader checks decoding of x-rh-identity header
+func TestDecodeIdentityHeader(t *testing.T) {
+	identity, err := server.DecodeIdentityHeader(encodeIdentity(
+		`{"identity": {"account_number": "6278", "org_id": "11789772", "user": {"user_id": "42"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, types.Identity{AccountNumber: "6278", OrgID: 11789772, UserID: "42"}, identity)
+
+	// organization ID from internal part of identity and user name
+	identity, err = server.DecodeIdentityHeader(encodeIdentity(
+		`{"identity": {"internal": {"org_id": "1"}, "user": {"username": "tester"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, types.Identity{OrgID: 1, UserID: "tester"}, identity)
+}
+
+// TestDecodeMalformedIdentityHeader checks that malformed identities are
+// rejected
+func TestDecodeMalformedIdentityHeader(t *testing.T) {
+	malformed := []string{
+		"not base64!",
+		encodeIdentity("not JSON"),
+		encodeIdentity(`{"identity": {"account_number": "6278"}}`),
+		encodeIdentity(`{"identity": {"org_id": "foobar"}}`),
+		encodeIdentity(`{"identity": {"org_id": "0"}}`),
+	}
+
+	for _, header := range malformed {
+		_, err := server.DecodeIdentityHeader(header)
+		assert.Error(t, err, header)
+	}
+}
+
+// TestPreflightWithoutIdentity checks that CORS preflight requests are not
+// rejected when authentication is enabled
+func TestPreflightWithoutIdentity(t *testing.T) {
+	config := server.Configuration{APIPrefix: "/api/", AuthType: server.AuthTypeXRH}
+	handler, _ := newTestHandler(t, config, nil)
+
+	request := httptest.NewRequest(http.MethodOptions, "/api/"+server.InfoEndpoint, http.NoBody)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// other methods still need identity
+	request = httptest.NewRequest(http.MethodGet, "/api/"+server.InfoEndpoint, http.NoBody)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
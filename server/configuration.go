@@ -22,4 +22,7 @@ type Configuration struct {
 	APIPrefix   string `mapstructure:"api_prefix" toml:"api_prefix"`
 	APISpecFile string `mapstructure:"api_spec_file" toml:"api_spec_file"`
 	Debug       bool   `mapstructure:"debug" toml:"debug"`
+	WatchFiles  bool   `mapstructure:"watch_files" toml:"watch_files"`
+	AuthType    string `mapstructure:"auth_type" toml:"auth_type"`
+	JWTKeyFile  string `mapstructure:"jwt_key_file" toml:"jwt_key_file"`
 }
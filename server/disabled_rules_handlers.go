@@ -0,0 +1,137 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// Handlers for endpoints to disable and re-enable rule for given cluster.
+// Disabled rules are omitted from served reports unless get_disabled=true
+// query parameter is used.
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/RedHatInsights/insights-operator-utils/responses"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// DisableRuleRequest represents optional body of request to disable rule
+type DisableRuleRequest struct {
+	Justification string `json:"justification"`
+}
+
+// disableRuleForCluster disables rule for given cluster
+func (server *HTTPServer) disableRuleForCluster(writer http.ResponseWriter, request *http.Request) {
+	clusterName, ruleID, ok := server.readClusterAndRule(writer, request)
+	if !ok {
+		return
+	}
+
+	// justification is optional
+	var disableRequest DisableRuleRequest
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &disableRequest)
+		if err != nil {
+			sendImproperParameter(writer, err)
+			return
+		}
+	}
+
+	server.Storage.DisableRuleForCluster(clusterName, ruleID, disableRequest.Justification)
+
+	log.Info().
+		Str("cluster", string(clusterName)).
+		Str("rule", string(ruleID)).
+		Str("justification", disableRequest.Justification).
+		Msg("Rule disabled")
+
+	err = responses.SendOK(writer, responses.BuildOkResponse())
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// enableRuleForCluster re-enables rule for given cluster
+func (server *HTTPServer) enableRuleForCluster(writer http.ResponseWriter, request *http.Request) {
+	clusterName, ruleID, ok := server.readClusterAndRule(writer, request)
+	if !ok {
+		return
+	}
+
+	server.Storage.EnableRuleForCluster(clusterName, ruleID)
+
+	log.Info().
+		Str("cluster", string(clusterName)).
+		Str("rule", string(ruleID)).
+		Msg("Rule enabled")
+
+	err := responses.SendOK(writer, responses.BuildOkResponse())
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// listOfDisabledRulesForCluster returns all rules disabled for given cluster
+func (server *HTTPServer) listOfDisabledRulesForCluster(writer http.ResponseWriter, request *http.Request) {
+	clusterName, err := readClusterName(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	report, err := server.Storage.ReadReportForCluster(clusterName)
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+
+	// description is taken from rule hits stored in cluster report
+	descriptions := make(map[types.RuleID]string)
+	parsed, err := parseReport(report)
+	if err != nil {
+		log.Error().Err(err).Msg(unableToProcessReportErrorMessage)
+	}
+	for _, hit := range parsed.ruleHits {
+		ruleID, _ := hit["rule_id"].(string)
+		description, _ := hit["description"].(string)
+		descriptions[types.RuleID(ruleID)] = description
+	}
+
+	disabledRules := server.Storage.ListOfDisabledRulesForCluster(clusterName)
+	rules := make([]types.DisabledRuleResponse, 0, len(disabledRules))
+	for _, disabledRule := range disabledRules {
+		rules = append(rules, types.DisabledRuleResponse{
+			RuleModule:    string(disabledRule.RuleID),
+			Description:   descriptions[disabledRule.RuleID],
+			DisabledAt:    disabledRule.DisabledAt.Format(time.RFC3339),
+			Justification: disabledRule.Justification,
+		})
+	}
+
+	err = responses.SendOK(writer, responses.BuildOkResponseWithData("rules", rules))
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
@@ -20,12 +20,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/RedHatInsights/insights-operator-utils/responses"
-	"github.com/RedHatInsights/insights-results-aggregator-mock/data"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
 )
 
@@ -74,6 +76,39 @@ type MetadataEntry struct {
 	HitsBySeverity  map[string]int `json:"hits_by_severity"`
 }
 
+// dvoRuleModulePrefix is prefix of modules with rule content for DVO checks
+const dvoRuleModulePrefix = "ccx_rules_ocp.external.dvo."
+
+// Range of severities of DVO checks
+const (
+	minDVOSeverity = 1
+	maxDVOSeverity = 4
+)
+
+// DVONamespaceInfoResponse structure represents information about one
+// namespace
+type DVONamespaceInfoResponse struct {
+	Status        string                `json:"status"`
+	ClusterEntry  ClusterEntry          `json:"cluster"`
+	Namespace     NamespaceEntry        `json:"namespace"`
+	MetadataEntry NamespaceInfoMetadata `json:"metadata"`
+}
+
+// NamespaceInfoMetadata structure contains timestamps of the last report
+// for namespace
+type NamespaceInfoMetadata struct {
+	ReportedAt    string `json:"reported_at"`
+	LastCheckedAt string `json:"last_checked_at"`
+}
+
+// DVONamespaceReportsResponse structure represents list of all
+// recommendations affecting one namespace
+type DVONamespaceReportsResponse struct {
+	Status          string              `json:"status"`
+	Namespace       NamespaceEntry      `json:"namespace"`
+	Recommendations []DVORecommendation `json:"recommendations"`
+}
+
 // DVORecommendation structure represents one DVO-related recommendation
 type DVORecommendation struct {
 	Check        string      `json:"check"`
@@ -91,9 +126,10 @@ type DVOObject struct {
 	UID  string `json:"uid"`
 }
 
-// allDVONamespaces handler returns list of all DVO namespaces. Currently it
-// does not depend on Organization ID as this information is passed through
-// Bearer token in real Smart Proxy service. The format of output should be:
+// allDVONamespaces handler returns list of all DVO namespaces from clusters
+// that belong to the organization of user that made the request. Namespaces
+// from all clusters are returned when authentication is disabled. The format
+// of output should be:
 //
 //		  {
 //		    "status": "ok",
@@ -118,42 +154,33 @@ type DVOObject struct {
 //	             },
 //		    ]
 //		}
-func (server *HTTPServer) allDVONamespaces(writer http.ResponseWriter, _ *http.Request) {
+func (server *HTTPServer) allDVONamespaces(writer http.ResponseWriter, request *http.Request) {
 	log.Info().Msg("All DVO namespaces handler")
 	// set the response header
 	writer.Header().Set(contentType, appJSON)
 
-	dvoWorkloads := data.DVOWorkloads
-	workloads := make([]Workload, 0, len(dvoWorkloads))
+	workloads := make([]Workload, 0)
 
-	for clusterUUID, workloadsForCluster := range dvoWorkloads {
+	for _, clusterUUID := range server.accessibleDVOClusters(request) {
+		workloadsForCluster, _ := server.Storage.GetDVOWorkloads(clusterUUID)
 		// retrieve set of all namespaces for given cluster
 		namespaces := getNamespaces(workloadsForCluster)
 		// construct one workload entry
 		for _, namespace := range namespaces {
 			numberOfRecommendations := numberOfRecommendations(workloadsForCluster, namespace)
+			highestSeverity, hitsBySeverity := server.severitiesForNamespace(workloadsForCluster, namespace)
+			metadata := server.dvoNamespaceMetadata(clusterUUID, namespace)
 
 			workload := Workload{
-				ClusterEntry{
-					UUID:        string(clusterUUID),
-					DisplayName: "Cluster name " + string(clusterUUID),
-				},
-				NamespaceEntry{
-					UUID:     namespace,
-					FullName: "Namespace name " + namespace,
-				},
+				server.dvoClusterEntry(clusterUUID),
+				dvoNamespaceEntry(namespace, metadata),
 				MetadataEntry{
 					Recommendations: numberOfRecommendations,
 					Objects:         numberOfObjects(workloadsForCluster, namespace),
-					ReportedAt:      time.Now().Format(time.RFC3339),
-					LastCheckedAt:   time.Now().Format(time.RFC3339),
-					HighestSeverity: 4,
-					HitsBySeverity: map[string]int{
-						"1": 0,
-						"2": 0,
-						"3": 0,
-						"4": numberOfRecommendations,
-					},
+					ReportedAt:      metadata.ReportedAt,
+					LastCheckedAt:   metadata.LastCheckedAt,
+					HighestSeverity: highestSeverity,
+					HitsBySeverity:  hitsBySeverity,
 				},
 			}
 			workloads = append(workloads, workload)
@@ -259,7 +286,13 @@ func (server *HTTPServer) dvoNamespaceForCluster(writer http.ResponseWriter, req
 	}
 	log.Info().Str("namespace selector", namespace).Msg("Query parameters")
 
-	workloadsForCluster, found := data.DVOWorkloads[types.ClusterName(cluster)]
+	// permissions are checked first, so users from other organizations can
+	// not distinguish existing clusters from unknown ones
+	if !server.checkDVOPermissions(writer, request, types.ClusterName(cluster)) {
+		return
+	}
+
+	workloadsForCluster, found := server.Storage.GetDVOWorkloads(types.ClusterName(cluster))
 	if !found {
 		message := fmt.Sprintf("DVO namespaces for cluster %s not found", cluster)
 		log.Info().Msg(message)
@@ -277,32 +310,146 @@ func (server *HTTPServer) dvoNamespaceForCluster(writer http.ResponseWriter, req
 	var responseData WorkloadsForCluster
 
 	// fill-in elementary metadata
+	metadata := server.dvoNamespaceMetadata(types.ClusterName(cluster), namespace)
 	responseData.Status = "ok"
-	responseData.ClusterEntry = ClusterEntry{
-		UUID:        cluster,
-		DisplayName: "Cluster name " + cluster,
-	}
-	responseData.Namespace = NamespaceEntry{
-		UUID:     namespace,
-		FullName: "Namespace name " + namespace,
-	}
-	numberOfRecommendations := numberOfRecommendations(workloadsForCluster, namespace)
+	responseData.ClusterEntry = server.dvoClusterEntry(types.ClusterName(cluster))
+	responseData.Namespace = dvoNamespaceEntry(namespace, metadata)
+	highestSeverity, hitsBySeverity := server.severitiesForNamespace(workloadsForCluster, namespace)
 	responseData.MetadataEntry = MetadataEntry{
-		Recommendations: numberOfRecommendations,
+		Recommendations: numberOfRecommendations(workloadsForCluster, namespace),
 		Objects:         numberOfObjects(workloadsForCluster, namespace),
-		ReportedAt:      time.Now().Format(time.RFC3339),
-		LastCheckedAt:   time.Now().Format(time.RFC3339),
-		HighestSeverity: 4,
-		HitsBySeverity: map[string]int{
-			"1": 0,
-			"2": 0,
-			"3": 0,
-			"4": numberOfRecommendations,
-		},
+		ReportedAt:      metadata.ReportedAt,
+		LastCheckedAt:   metadata.LastCheckedAt,
+		HighestSeverity: highestSeverity,
+		HitsBySeverity:  hitsBySeverity,
 	}
 
 	// fill-in all recommendations
-	responseData.Recommendations = recommendationsForNamespace(workloadsForCluster, namespace)
+	responseData.Recommendations = server.recommendationsForNamespace(workloadsForCluster, namespace)
+
+	// transform response structure into proper JSON payload
+	bytes, err := json.MarshalIndent(responseData, "", "\t")
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+		return
+	}
+
+	// and send the response to client
+	_, err = writer.Write(bytes)
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// dvoNamespaceInfo implements handler for endpoint that returns information
+// about selected namespace. The format of the output should be:
+//
+//	{
+//	    "status": "ok",
+//	    "cluster": {
+//	        "uuid": "{cluster UUID}",
+//	        "display_name": "{cluster UUID or displayable name}",
+//	    },
+//	    "namespace": {
+//	        "uuid": "{namespace UUID}",
+//	        "name": "{namespace real name}",
+//	    },
+//	    "metadata": {
+//	        "reported_at": "{reported_at}",
+//	        "last_checked_at": "{last_checked_at}",
+//	    },
+//	}
+//
+// 404 Not Found is returned for unknown namespace, 403 Forbidden when the
+// namespace belongs to cluster from other organization or when user is not
+// known.
+func (server *HTTPServer) dvoNamespaceInfo(writer http.ResponseWriter, request *http.Request) {
+	log.Info().Msg("DVO namespace info handler")
+
+	clusters, namespace, ok := server.readDVONamespace(writer, request)
+	if !ok {
+		// everything has been handled already
+		return
+	}
+
+	// set the response header
+	writer.Header().Set(contentType, appJSON)
+
+	// prepare response structure
+	cluster := clusters[0]
+	metadata := server.dvoNamespaceMetadata(cluster, namespace)
+	var responseData DVONamespaceInfoResponse
+	responseData.Status = "ok"
+	responseData.ClusterEntry = server.dvoClusterEntry(cluster)
+	responseData.Namespace = dvoNamespaceEntry(namespace, metadata)
+	responseData.MetadataEntry = NamespaceInfoMetadata{
+		ReportedAt:    metadata.ReportedAt,
+		LastCheckedAt: metadata.LastCheckedAt,
+	}
+
+	// transform response structure into proper JSON payload
+	bytes, err := json.MarshalIndent(responseData, "", "\t")
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+		return
+	}
+
+	// and send the response to client
+	_, err = writer.Write(bytes)
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// dvoNamespaceReports implements handler for endpoint that returns list of
+// all recommendations affecting selected namespace on all clusters. The
+// format of the output should be:
+//
+//	{
+//	    "status": "ok",
+//	    "namespace": {
+//	        "uuid": "{namespace UUID}",
+//	        "name": "{namespace real name}",
+//	    },
+//	    "recommendations": [
+//	        {
+//	            "check": "{rule name}",
+//	            "details": "{description}",
+//	            "resolution": "{remediation}",
+//	            "modified": "{timestamp}",
+//	            "more_info": "{more info}",
+//	            "extra_data": {template data},
+//	            "objects": [
+//	                {
+//	                    "kind": "{object kind}",
+//	                    "uid": "{object UUID}"
+//	                },
+//	            ],
+//	        },
+//	    ]
+//	}
+//
+// Only clusters that can be accessed by user are taken into account. Empty
+// list of recommendations is returned when no rules are hitting the
+// namespace. Error responses are the same as for namespace info endpoint.
+func (server *HTTPServer) dvoNamespaceReports(writer http.ResponseWriter, request *http.Request) {
+	log.Info().Msg("DVO namespace reports handler")
+
+	clusters, namespace, ok := server.readDVONamespace(writer, request)
+	if !ok {
+		// everything has been handled already
+		return
+	}
+
+	// set the response header
+	writer.Header().Set(contentType, appJSON)
+
+	// prepare response structure
+	workloads := server.workloadsForNamespace(clusters, namespace)
+	var responseData DVONamespaceReportsResponse
+	responseData.Status = "ok"
+	responseData.Namespace = dvoNamespaceEntry(namespace, server.dvoNamespaceMetadata(clusters[0], namespace))
+	responseData.Recommendations = server.recommendationsForNamespace(workloads, namespace)
 
 	// transform response structure into proper JSON payload
 	bytes, err := json.MarshalIndent(responseData, "", "\t")
@@ -318,6 +465,171 @@ func (server *HTTPServer) dvoNamespaceForCluster(writer http.ResponseWriter, req
 	}
 }
 
+// readDVONamespace reads namespace ID from request and finds all clusters
+// with such namespace that can be accessed by user. Error response is sent
+// when namespace is unknown or can not be accessed by user.
+func (server *HTTPServer) readDVONamespace(
+	writer http.ResponseWriter, request *http.Request,
+) ([]types.ClusterName, string, bool) {
+	namespace, err := getRouterParam(request, "namespace_id")
+	if err != nil {
+		err = responses.SendBadRequest(writer, err.Error())
+		if err != nil {
+			log.Error().Err(err).Msg(responseDataError)
+		}
+		return nil, "", false
+	}
+	log.Info().Str("namespace selector", namespace).Msg("Query parameters")
+
+	clusters := server.clustersForNamespace(server.Storage.ListOfDVOClusters(), namespace)
+	if len(clusters) == 0 {
+		message := fmt.Sprintf("DVO namespace %s not found", namespace)
+		log.Info().Msg(message)
+		err = responses.SendNotFound(writer, message)
+		if err != nil {
+			log.Error().Err(err).Msg(responseDataError)
+		}
+		return nil, "", false
+	}
+
+	// permissions are checked for the first cluster from other organization
+	// when user can not access any cluster with the namespace
+	accessible := server.clustersForNamespace(server.accessibleDVOClusters(request), namespace)
+	if len(accessible) > 0 {
+		clusters = accessible
+	}
+	if !server.checkDVOPermissions(writer, request, clusters[0]) {
+		return nil, "", false
+	}
+
+	return clusters, namespace, true
+}
+
+// clustersForNamespace returns clusters from given list that contain given
+// namespace
+func (server *HTTPServer) clustersForNamespace(clusters []types.ClusterName, namespace string) []types.ClusterName {
+	found := make([]types.ClusterName, 0)
+	for _, cluster := range clusters {
+		dvoCluster, _ := server.Storage.GetDVOCluster(cluster)
+		if _, ok := dvoCluster.Namespaces[namespace]; ok {
+			found = append(found, cluster)
+		}
+	}
+	return found
+}
+
+// workloadsForNamespace returns workloads from given namespace on given
+// clusters. The same workload reported for more clusters is returned just
+// once.
+func (server *HTTPServer) workloadsForNamespace(clusters []types.ClusterName, namespace string) []types.DVOWorkload {
+	var workloads []types.DVOWorkload
+	seen := make(map[types.DVOWorkload]struct{})
+
+	for _, cluster := range clusters {
+		workloadsForCluster, _ := server.Storage.GetDVOWorkloads(cluster)
+		for _, workload := range workloadsForCluster {
+			if workload.NamespaceUID != namespace {
+				continue
+			}
+			if _, found := seen[workload]; found {
+				continue
+			}
+			seen[workload] = struct{}{}
+			workloads = append(workloads, workload)
+		}
+	}
+	return workloads
+}
+
+// canAccessDVOCluster checks if cluster with DVO workloads belongs to the
+// organization of user that made the request. All clusters can be accessed
+// when authentication is disabled.
+func (server *HTTPServer) canAccessDVOCluster(request *http.Request, cluster types.ClusterName) bool {
+	identity, authenticated := readIdentity(request)
+	if !authenticated {
+		return true
+	}
+	dvoCluster, found := server.Storage.GetDVOCluster(cluster)
+	return found && dvoCluster.OrgID == identity.OrgID
+}
+
+// accessibleDVOClusters returns all clusters with DVO workloads that can be
+// accessed by user that made the request
+func (server *HTTPServer) accessibleDVOClusters(request *http.Request) []types.ClusterName {
+	clusters := make([]types.ClusterName, 0)
+	for _, cluster := range server.Storage.ListOfDVOClusters() {
+		if server.canAccessDVOCluster(request, cluster) {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+// checkDVOPermissions checks if user that made the request can access DVO
+// data for given cluster. The cluster needs to belong to the organization of
+// the user and user ID needs to be known. All clusters can be accessed when
+// authentication is disabled.
+func (server *HTTPServer) checkDVOPermissions(
+	writer http.ResponseWriter, request *http.Request, cluster types.ClusterName,
+) bool {
+	identity, authenticated := readIdentity(request)
+	if !authenticated {
+		return true
+	}
+
+	var message string
+	switch {
+	case identity.UserID == "":
+		message = "user ID is not set in identity"
+	case !server.canAccessDVOCluster(request, cluster):
+		message = fmt.Sprintf("you have no permissions to access cluster %s from organization %d",
+			cluster, identity.OrgID)
+	default:
+		return true
+	}
+
+	log.Error().Msg(message)
+	err := responses.SendForbidden(writer, message)
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+	return false
+}
+
+// dvoClusterEntry returns UUID and display name of cluster with DVO
+// workloads. Cluster UUID is used when display name is not known.
+func (server *HTTPServer) dvoClusterEntry(cluster types.ClusterName) ClusterEntry {
+	displayName := string(cluster)
+	dvoCluster, found := server.Storage.GetDVOCluster(cluster)
+	if found && dvoCluster.DisplayName != "" {
+		displayName = dvoCluster.DisplayName
+	}
+	return ClusterEntry{
+		UUID:        string(cluster),
+		DisplayName: displayName,
+	}
+}
+
+// dvoNamespaceMetadata returns name and timestamps of namespace stored in
+// given cluster
+func (server *HTTPServer) dvoNamespaceMetadata(cluster types.ClusterName, namespace string) storage.DVONamespace {
+	dvoCluster, _ := server.Storage.GetDVOCluster(cluster)
+	return dvoCluster.Namespaces[namespace]
+}
+
+// dvoNamespaceEntry returns UUID and name of namespace. Namespace UUID is
+// used when name is not known.
+func dvoNamespaceEntry(namespace string, metadata storage.DVONamespace) NamespaceEntry {
+	name := metadata.Name
+	if name == "" {
+		name = namespace
+	}
+	return NamespaceEntry{
+		UUID:     namespace,
+		FullName: name,
+	}
+}
+
 // getNamespaces returns set of all namespaces, i.e. all items will be unique
 func getNamespaces(workloads []types.DVOWorkload) []string {
 	// set of all namespaces for given cluster
@@ -334,6 +646,9 @@ func getNamespaces(workloads []types.DVOWorkload) []string {
 		keys = append(keys, key)
 	}
 
+	// stable order of namespaces
+	sort.Strings(keys)
+
 	return keys
 }
 
@@ -366,9 +681,55 @@ func numberOfObjects(workloads []types.DVOWorkload, namespace string) int {
 	return objects
 }
 
+// dvoRuleSeverity returns severity of DVO check. Severity is total risk of
+// rule stored in rule content under module with dvoRuleModulePrefix and
+// error key with upper-cased check name. The lowest severity is used for
+// checks without content.
+func (server *HTTPServer) dvoRuleSeverity(rule string) int {
+	ruleID := types.RuleID(dvoRuleModulePrefix + rule)
+	errorKey := types.ErrorKey(strings.ToUpper(rule))
+
+	ruleWithContent, err := server.Storage.GetRuleWithContent(ruleID, errorKey)
+	if err != nil {
+		log.Warn().Str("check", rule).Msg("Rule content for DVO check not found")
+		return minDVOSeverity
+	}
+	return min(max(ruleWithContent.TotalRisk, minDVOSeverity), maxDVOSeverity)
+}
+
+// severitiesForNamespace computes the highest severity of recommendations
+// for a cluster and namespace and number of recommendations for each
+// severity
+func (server *HTTPServer) severitiesForNamespace(workloads []types.DVOWorkload, namespace string) (int, map[string]int) {
+	// all buckets are always returned
+	hitsBySeverity := make(map[string]int, maxDVOSeverity)
+	for severity := minDVOSeverity; severity <= maxDVOSeverity; severity++ {
+		hitsBySeverity[strconv.Itoa(severity)] = 0
+	}
+
+	// set of unique rules
+	var rules = make(map[string]struct{})
+
+	highestSeverity := 0
+	for _, workload := range workloads {
+		if workload.NamespaceUID != namespace {
+			continue
+		}
+		if _, found := rules[workload.Rule]; found {
+			continue
+		}
+		rules[workload.Rule] = struct{}{}
+
+		severity := server.dvoRuleSeverity(workload.Rule)
+		hitsBySeverity[strconv.Itoa(severity)]++
+		highestSeverity = max(highestSeverity, severity)
+	}
+	return highestSeverity, hitsBySeverity
+}
+
 // recommendationsForNamespace constructs "recommendations" structure for DVO
 // reports all from specified namespace
-func recommendationsForNamespace(workloads []types.DVOWorkload, namespace string) []DVORecommendation {
+func (server *HTTPServer) recommendationsForNamespace(workloads []types.DVOWorkload, namespace string) []DVORecommendation {
 	// return value
 	// (we don't know size of the slice, so it is empty at beginning)
 	recommendations := make([]DVORecommendation, 0)
@@ -389,9 +750,9 @@ func recommendationsForNamespace(workloads []types.DVOWorkload, namespace string
 					Check:        workload.Rule,
 					Details:      workload.CheckDescription,
 					Resolution:   workload.CheckRemediation,
-					Modified:     modifiedForRule(workload.Rule),
-					MoreInfo:     moreInfoForRule(workload.Rule),
-					TemplateData: templateDataForRule(workload.Rule),
+					Modified:     server.modifiedForRule(workload.Rule),
+					MoreInfo:     server.moreInfoForRule(workload.Rule),
+					TemplateData: server.templateDataForRule(workload.Rule),
 					Objects:      objectsForRule(workloads, namespace, workload.Rule),
 				}
 				// add the newly found recommendation into the slice
@@ -404,33 +765,33 @@ func recommendationsForNamespace(workloads []types.DVOWorkload, namespace string
 }
 
 // moreInfoForRule function retrieves `more_info` field value for given rule
-func moreInfoForRule(rule string) string {
+func (server *HTTPServer) moreInfoForRule(rule string) string {
 	const defaultMoreInfoValue = "no additional info provided for this rule"
-	moreInfo, found := data.DVOMoreInfoMap[rule]
-	if !found {
+	check, found := server.Storage.GetDVOCheck(rule)
+	if !found || check.MoreInfo == "" {
 		return defaultMoreInfoValue
 	}
-	return moreInfo
+	return check.MoreInfo
 }
 
 // templateDataForRule function retrieves `extra_data` field value for given rule
-func templateDataForRule(rule string) interface{} {
+func (server *HTTPServer) templateDataForRule(rule string) interface{} {
 	var emptyExtraData = struct{}{}
-	extraData, found := data.DVOTemplateDataMap[rule]
-	if !found {
+	check, found := server.Storage.GetDVOCheck(rule)
+	if !found || check.TemplateData == nil {
 		return emptyExtraData
 	}
-	return extraData
+	return check.TemplateData
 }
 
 // modifiedForRule function retrieves `modified` field value for given rule
-func modifiedForRule(rule string) string {
+func (server *HTTPServer) modifiedForRule(rule string) string {
 	const defaultModifiedTimestamp = "1999-01-02T03:04:05Z"
-	modified, found := data.DVOModifiedMap[rule]
-	if !found {
+	check, found := server.Storage.GetDVOCheck(rule)
+	if !found || check.Modified == "" {
 		return defaultModifiedTimestamp
 	}
-	return modified
+	return check.Modified
 }
 
 func objectsForRule(workloads []types.DVOWorkload, namespace, rule string) []DVOObject {
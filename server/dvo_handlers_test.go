@@ -17,11 +17,15 @@ limitations under the License.
 package server_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
 )
@@ -77,3 +81,144 @@ func TestNumberOfObjects(t *testing.T) {
 
 	assert.Equal(t, expected, count)
 }
+
+// TestDVOSeverities checks that severities of DVO recommendations are taken
+// from total risk stored in rule content
+func TestDVOSeverities(t *testing.T) {
+	// e6ed9bb3 namespace is hit by 7 rules with all severities
+	const url = "/api/namespaces/dvo/e6ed9bb3-efc3-46a6-b3ae-3f1a6e59546c/cluster/00000001-0001-0001-0001-000000000001"
+
+	ruleContent, err := content.ParseContent("../content.json")
+	assert.NoError(t, err)
+
+	handler, _ := newTestHandler(t, server.Configuration{APIPrefix: "/api/"}, ruleContent)
+
+	request := httptest.NewRequest(http.MethodGet, url, http.NoBody)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response server.WorkloadsForCluster
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	assert.Equal(t, 7, response.MetadataEntry.Recommendations)
+	assert.Equal(t, 4, response.MetadataEntry.HighestSeverity)
+	assert.Equal(t, map[string]int{"1": 2, "2": 2, "3": 2, "4": 1}, response.MetadataEntry.HitsBySeverity)
+}
+
+// TestDVONamespacesOrgScoping checks that DVO namespaces from clusters that
+// belong to other organization are not returned
+func TestDVONamespacesOrgScoping(t *testing.T) {
+	config := server.Configuration{APIPrefix: "/api/", AuthType: server.AuthTypeXRH}
+	handler, _ := newTestHandler(t, config, nil)
+
+	readWorkloads := func(orgID string) []server.Workload {
+		request := httptest.NewRequest(http.MethodGet, "/api/namespaces/dvo", http.NoBody)
+		request.Header.Set(server.IdentityHeader, encodeIdentity(
+			`{"identity": {"org_id": "`+orgID+`", "user": {"user_id": "tester"}}}`))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var response server.AllDVONamespacesResponse
+		assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		return response.Workloads
+	}
+
+	// cluster from organization 2 is not returned for organization 1
+	workloads := readWorkloads("1")
+	assert.NotEmpty(t, workloads)
+	for _, workload := range workloads {
+		assert.NotEqual(t, "00000002-0002-0002-0002-000000000001", workload.ClusterEntry.UUID)
+		assert.NotEqual(t, workload.ClusterEntry.UUID, workload.ClusterEntry.DisplayName)
+		assert.NotEqual(t, workload.Namespace.UUID, workload.Namespace.FullName)
+	}
+
+	workloads = readWorkloads("2")
+	assert.Len(t, workloads, 1)
+	assert.Equal(t, "org2-dvo-cluster-01", workloads[0].ClusterEntry.DisplayName)
+
+	assert.Empty(t, readWorkloads("3"))
+}
+
+// TestDVONamespaceInfoPermissions checks that namespace from cluster owned by
+// other organization or requested by unknown user can not be accessed
+func TestDVONamespaceInfoPermissions(t *testing.T) {
+	const url = "/api/namespaces/dvo/e6ed9bb3-efc3-46a6-b3ae-3f1a6e59546c/info"
+
+	config := server.Configuration{APIPrefix: "/api/", AuthType: server.AuthTypeXRH}
+	handler, _ := newTestHandler(t, config, nil)
+
+	readInfo := func(identity string) int {
+		request := httptest.NewRequest(http.MethodGet, url, http.NoBody)
+		request.Header.Set(server.IdentityHeader, encodeIdentity(identity))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK,
+		readInfo(`{"identity": {"org_id": "1", "user": {"user_id": "tester"}}}`))
+	assert.Equal(t, http.StatusForbidden,
+		readInfo(`{"identity": {"org_id": "2", "user": {"user_id": "tester"}}}`))
+	assert.Equal(t, http.StatusForbidden,
+		readInfo(`{"identity": {"org_id": "1"}}`))
+}
+
+// TestDVONamespaceReports checks list of recommendations for namespace that
+// is not hit by any rule and access to namespace from other organization or
+// by unknown user
+func TestDVONamespaceReports(t *testing.T) {
+	const url = "/api/namespaces/dvo/2c4b9e1a-8f3d-4f6b-a7e5-0d9c3b1e6f42/reports"
+
+	config := server.Configuration{APIPrefix: "/api/", AuthType: server.AuthTypeXRH}
+	handler, _ := newTestHandler(t, config, nil)
+
+	readReports := func(identity string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, url, http.NoBody)
+		request.Header.Set(server.IdentityHeader, encodeIdentity(identity))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := readReports(`{"identity": {"org_id": "1", "user": {"user_id": "tester"}}}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{
+		"status": "ok",
+		"namespace": {"uuid": "2c4b9e1a-8f3d-4f6b-a7e5-0d9c3b1e6f42", "name": "openshift-monitoring"},
+		"recommendations": []
+	}`, recorder.Body.String())
+
+	recorder = readReports(`{"identity": {"org_id": "2", "user": {"user_id": "tester"}}}`)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+
+	recorder = readReports(`{"identity": {"org_id": "1"}}`)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+// TestDVONamespaceForClusterPermissions checks that users from other
+// organization can not distinguish existing clusters from unknown ones
+func TestDVONamespaceForClusterPermissions(t *testing.T) {
+	config := server.Configuration{APIPrefix: "/api/", AuthType: server.AuthTypeXRH}
+	handler, _ := newTestHandler(t, config, nil)
+
+	readNamespace := func(orgID, cluster string) int {
+		request := httptest.NewRequest(http.MethodGet,
+			"/api/cluster/"+cluster+"/namespaces/dvo/fbcbe2d3-e398-4b40-9d5e-4eb46fe8286f", http.NoBody)
+		request.Header.Set(server.IdentityHeader, encodeIdentity(
+			`{"identity": {"org_id": "`+orgID+`", "user": {"user_id": "tester"}}}`))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	const (
+		existingCluster = "00000001-0001-0001-0001-000000000002"
+		unknownCluster  = "00000001-0001-0001-0001-0000000000ff"
+	)
+
+	assert.Equal(t, http.StatusOK, readNamespace("1", existingCluster))
+	assert.Equal(t, http.StatusForbidden, readNamespace("2", existingCluster))
+	assert.Equal(t, http.StatusForbidden, readNamespace("2", unknownCluster))
+}
@@ -62,10 +62,14 @@ const (
 	RuleGroupsEndpoint = "groups"
 	// ClustersForOrganizationEndpoint returns all clusters for {organization}
 	ClustersForOrganizationEndpoint = "organizations/{organization}/clusters"
+	// ClusterInOrganizationEndpoint adds or removes {cluster} to/from {organization}. DEBUG only
+	ClusterInOrganizationEndpoint = "organizations/{organization}/clusters/{cluster}"
 	// DisableRuleForClusterEndpoint disables a rule for specified cluster
 	DisableRuleForClusterEndpoint = "clusters/{cluster}/rules/{rule_id}/disable"
 	// EnableRuleForClusterEndpoint re-enables a rule for specified cluster
 	EnableRuleForClusterEndpoint = "clusters/{cluster}/rules/{rule_id}/enable"
+	// DisabledRulesForClusterEndpoint returns all rules disabled for specified cluster
+	DisabledRulesForClusterEndpoint = "clusters/{cluster}/rules/disabled"
 	// RuleClusterDetailEndpoint should return a list of all the clusters IDs affected by this rule
 	RuleClusterDetailEndpoint = "rule/{rule_selector}/clusters_detail/"
 
@@ -85,6 +89,10 @@ const (
 	// cluster and requestID
 	RuleHitsForRequestID = "cluster/{cluster}/request/{request_id}/report"
 
+	// TriggerGathering simulates on demand data gathering run for given
+	// cluster and returns new request ID
+	TriggerGathering = "cluster/{cluster}/gathering"
+
 	// Endpoints to acknowledge rule and to manipulate with
 	// acknowledgements.
 
@@ -117,6 +125,9 @@ const (
 	// UpgradeRisksPredictionEndpoint returns the prediction about upgrading
 	// the given cluster.
 	UpgradeRisksPredictionEndpoint = "cluster/{cluster}/upgrade-risks-prediction"
+	// UpgradeRisksPredictionHistoryEndpoint returns past predictions about
+	// upgrading the given cluster made in selected time range.
+	UpgradeRisksPredictionHistoryEndpoint = "cluster/{cluster}/upgrade-risks-prediction/history"
 	// UpgradeRisksPredictionMultiClusterEndpoint returns the predictions about
 	// upgrading a list of clusters
 	UpgradeRisksPredictionMultiClusterEndpoint = "upgrade-risks-prediction" // #nosec G101
@@ -127,6 +138,10 @@ const (
 	// ExitEndpoint perform server shutdown (in Debug mode only)
 	ExitEndpoint = "exit"
 
+	// ReloadEndpoint re-reads mock data, rule content and groups (in Debug
+	// mode only)
+	ReloadEndpoint = "reload"
+
 	// AllDVONamespaces endpoint address.
 	//
 	// Returns the list of all DVO namespaces (i.e. array of objects) to
@@ -15,7 +15,13 @@
 package server
 
 import (
+	"errors"
+	"net/http"
+
+	"github.com/RedHatInsights/insights-operator-utils/responses"
 	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
 )
 
 // responseDataError is used as the error message when the responses functions return an error
@@ -25,3 +31,30 @@ const responseDataError = "Unexpected error during response data encoding"
 func handleServerError(err error) {
 	log.Error().Err(err).Msg("handleServerError()")
 }
+
+// sendStorageError sends response with status code that corresponds to
+// error returned from storage
+func sendStorageError(writer http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, storage.ErrClusterNotFound), errors.Is(err, storage.ErrRuleNotFound):
+		err = responses.SendNotFound(writer, err.Error())
+	case errors.Is(err, storage.ErrForbiddenOrganization):
+		err = responses.SendForbidden(writer, err.Error())
+	case errors.Is(err, storage.ErrMalformedReport):
+		err = responses.SendBadRequest(writer, err.Error())
+	default:
+		err = responses.SendInternalServerError(writer, err.Error())
+	}
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// sendImproperParameter sends response for request with improper parameter
+func sendImproperParameter(writer http.ResponseWriter, err error) {
+	log.Error().Err(err).Msg(requestParameter)
+	err = responses.SendBadRequest(writer, err.Error())
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
@@ -29,4 +29,6 @@ var (
 	GetNamespaces           = getNamespaces
 	NumberOfRecommendations = numberOfRecommendations
 	NumberOfObjects         = numberOfObjects
+	DecodeIdentityHeader    = decodeIdentityHeader
+	VerifyToken             = verifyToken
 )
@@ -0,0 +1,155 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// On demand data gathering trigger. Each gathering run gets new request ID
+// that is attached to the cluster. Simplified report for the request ID is
+// derived from the current report of the cluster and it is available when the
+// request is processed.
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"net/http"
+
+	"github.com/RedHatInsights/insights-operator-utils/responses"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// requestIDLength is length of request IDs generated for gathering runs
+const requestIDLength = 26
+
+// requestIDAlphabet contains all characters used in generated request IDs
+const requestIDAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// newRequestID generates random request ID in the same format as request IDs
+// stored in mock data
+func newRequestID() (types.RequestID, error) {
+	limit := big.NewInt(int64(len(requestIDAlphabet)))
+
+	requestID := make([]byte, requestIDLength)
+	for i := range requestID {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		requestID[i] = requestIDAlphabet[n.Int64()]
+	}
+	return types.RequestID(requestID), nil
+}
+
+// totalRisk reads total risk of rule hit that can be stored as a number read
+// from report or as an integer taken from rule content
+func totalRisk(value interface{}) int {
+	switch v := value.(type) {
+	case json.Number:
+		risk, err := v.Int64()
+		if err != nil {
+			return 0
+		}
+		return int(risk)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// simplifiedRuleHits derives simplified rule hits from report of given
+// cluster. Missing attributes are filled from rule content.
+func (server *HTTPServer) simplifiedRuleHits(report types.ClusterReport) ([]types.SimplifiedRuleHit, error) {
+	parsed, err := parseReport(report)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleHits := make([]types.SimplifiedRuleHit, 0, len(parsed.ruleHits))
+	for _, hit := range parsed.ruleHits {
+		server.fillRuleHit(hit)
+
+		ruleID, _ := hit["rule_id"].(string)
+		details, _ := hit["details"].(map[string]interface{})
+		errorKey, _ := details["error_key"].(string)
+		description, _ := hit["description"].(string)
+
+		ruleHits = append(ruleHits, types.SimplifiedRuleHit{
+			RuleFQDN:    ruleID + ".report",
+			ErrorKey:    errorKey,
+			Description: description,
+			TotalRisk:   totalRisk(hit["total_risk"]),
+		})
+	}
+	return ruleHits, nil
+}
+
+// triggerGathering method implements endpoint that simulates on demand data
+// gathering run for given cluster. New request ID is returned and the
+// simplified report is available when the request is processed.
+func (server *HTTPServer) triggerGathering(writer http.ResponseWriter, request *http.Request) {
+	clusterName, err := readClusterName(writer, request)
+	if err != nil {
+		err = responses.SendBadRequest(writer, err.Error())
+		if err != nil {
+			log.Error().Err(err).Msg(responseDataError)
+		}
+		return
+	}
+	logClusterName(clusterName)
+
+	report, err := server.Storage.ReadReportForCluster(clusterName)
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+
+	ruleHits, err := server.simplifiedRuleHits(report)
+	if err != nil {
+		log.Error().Err(err).Msg(unableToProcessReportErrorMessage)
+		err = responses.SendInternalServerError(writer, unableToProcessReportErrorMessage)
+		if err != nil {
+			log.Error().Err(err).Msg(responseDataError)
+		}
+		return
+	}
+
+	requestID, err := newRequestID()
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to generate request ID")
+		err = responses.SendInternalServerError(writer, err.Error())
+		if err != nil {
+			log.Error().Err(err).Msg(responseDataError)
+		}
+		return
+	}
+	logRequestID(requestID)
+
+	requestInfo := server.Storage.AddRequestForCluster(clusterName, requestID, ruleHits)
+
+	// prepare data structure
+	responseData := map[string]interface{}{statusKey: "ok"}
+	responseData["cluster"] = string(clusterName)
+	responseData["requestID"] = string(requestID)
+	responseData["requestStatus"] = requestInfo.Status
+
+	err = responses.SendAccepted(writer, responseData)
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+const gatheringCluster = "00000001-624a-49a5-bab8-4fdc5e51a266"
+
+// triggerGathering sends request to trigger data gathering for given cluster
+func triggerGathering(t *testing.T, handler http.Handler, cluster string) (int, map[string]string) {
+	request := httptest.NewRequest(http.MethodPost, "/api/cluster/"+cluster+"/gathering", http.NoBody)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	response := map[string]string{}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	return recorder.Code, response
+}
+
+// readSimplifiedReport reads simplified report for given request ID
+func readSimplifiedReport(t *testing.T, handler http.Handler, cluster, requestID string) types.SimplifiedReport {
+	request := httptest.NewRequest(http.MethodGet,
+		"/api/cluster/"+cluster+"/request/"+requestID+"/report", http.NoBody)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var report types.SimplifiedReport
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&report))
+	return report
+}
+
+// TestTriggerGathering checks that gathering run produces new request ID with
+// simplified report derived from the current report of cluster
+func TestTriggerGathering(t *testing.T) {
+	config := server.Configuration{APIPrefix: "/api/", AuthType: server.AuthTypeNone}
+	handler, storageInstance := newTestHandler(t, config, nil)
+
+	code, response := triggerGathering(t, handler, gatheringCluster)
+	assert.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, gatheringCluster, response["cluster"])
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-z]{26}$`), response["requestID"])
+
+	// no delays are configured, so the request is processed immediately
+	report := readSimplifiedReport(t, handler, gatheringCluster, response["requestID"])
+	assert.Equal(t, storage.RequestStatusProcessed, report.Status)
+	assert.NotEmpty(t, report.RuleHits)
+	assert.Equal(t, "ccx_rules_ocp.external.rules.node_installer_degraded.report", report.RuleHits[0].RuleFQDN)
+	assert.Equal(t, "NODE_INSTALLER_DEGRADED", report.RuleHits[0].ErrorKey)
+	assert.Equal(t, 3, report.RuleHits[0].TotalRisk)
+
+	// the request ID is attached to the cluster
+	requests, found := storageInstance.ListOfRequestsForCluster(gatheringCluster)
+	assert.True(t, found)
+	assert.Len(t, requests, 1)
+
+	// rule hits are not available before the request is processed
+	storageInstance.SetRequestsConfiguration(storage.RequestsConfiguration{ReceivedDelay: time.Hour})
+	code, response = triggerGathering(t, handler, gatheringCluster)
+	assert.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, storage.RequestStatusReceived, response["requestStatus"])
+
+	report = readSimplifiedReport(t, handler, gatheringCluster, response["requestID"])
+	assert.Equal(t, storage.RequestStatusReceived, report.Status)
+	assert.Empty(t, report.RuleHits)
+
+	// cluster without report
+	code, _ = triggerGathering(t, handler, "00000000-0000-0000-0000-000000000000")
+	assert.Equal(t, http.StatusNotFound, code)
+}
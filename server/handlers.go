@@ -36,8 +36,10 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 
+	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/data"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/groups"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
 )
 
@@ -46,12 +48,13 @@ const failureClusterIDPrefix = "ffffffff-ffff-ffff-ffff-"
 const requestParameter = "Request parameter"
 
 const unableToReadReportErrorMessage = "Unable to read report for cluster"
+const unableToProcessReportErrorMessage = "Unable to process report for cluster"
 const requestsForClusterNotFound = "Requests for cluster not found"
 
 const statusKey = "status"
 
 // StatusProcessed is message returned for already processed reports (rule hits)
-const StatusProcessed = "processed"
+const StatusProcessed = storage.RequestStatusProcessed
 
 // readOrganizationID retrieves organization id from request
 // if it's not possible, it writes http error to the writer and returns error
@@ -138,6 +141,73 @@ func readRequestID(_ http.ResponseWriter, request *http.Request) (types.RequestI
 	return validatedRequestID, nil
 }
 
+// readRuleID retrieves rule ID (rule module) from request
+func readRuleID(_ http.ResponseWriter, request *http.Request) (types.RuleID, error) {
+	ruleID, err := getRouterParam(request, "rule_id")
+	if err != nil {
+		return "", err
+	}
+
+	IDValidator := regexp.MustCompile(`^[a-zA-Z_0-9]+(\.[a-zA-Z_0-9]+)*$`)
+	if !IDValidator.MatchString(ruleID) {
+		return "", fmt.Errorf("invalid rule ID: '%s'", ruleID)
+	}
+
+	return types.RuleID(ruleID), nil
+}
+
+// readErrorKey retrieves rule error key from request
+func readErrorKey(_ http.ResponseWriter, request *http.Request) (types.ErrorKey, error) {
+	errorKey, err := getRouterParam(request, "error_key")
+	if err != nil {
+		return "", err
+	}
+
+	errorKeyValidator := regexp.MustCompile(`^[a-zA-Z_0-9]+$`)
+	if !errorKeyValidator.MatchString(errorKey) {
+		return "", fmt.Errorf("invalid error key: '%s'", errorKey)
+	}
+
+	return types.ErrorKey(errorKey), nil
+}
+
+// readClusterAndRule retrieves cluster name and rule ID from request. Bad
+// request or not found response is sent when parameters are not correct.
+func (server *HTTPServer) readClusterAndRule(
+	writer http.ResponseWriter, request *http.Request,
+) (types.ClusterName, types.RuleID, bool) {
+	clusterName, err := readClusterName(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return "", "", false
+	}
+
+	ruleID, err := readRuleID(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return "", "", false
+	}
+
+	// only known clusters can be used
+	_, err = server.Storage.ReadReportForCluster(clusterName)
+	if err != nil {
+		sendStorageError(writer, err)
+		return "", "", false
+	}
+
+	return clusterName, ruleID, true
+}
+
+// readUserID retrieves ID of user that made the request. The default user
+// is used when authentication is disabled or user ID is not set in identity.
+func readUserID(request *http.Request) types.UserID {
+	identity, _ := readIdentity(request)
+	if identity.UserID == "" {
+		return defaultUserName
+	}
+	return identity.UserID
+}
+
 // getRouterParam retrieves parameter from URL like `/organization/{org_id}`
 func getRouterParam(request *http.Request, paramName string) (string, error) {
 	value, found := mux.Vars(request)[paramName]
@@ -194,12 +264,12 @@ func (server *HTTPServer) serveAPISpecFile(writer http.ResponseWriter, request *
 func (server *HTTPServer) serveContentWithGroups(writer http.ResponseWriter, _ *http.Request) {
 	log.Info().Msg("Content with groups handler")
 
-	server.initGroupList()
+	groupsList := server.getGroupList()
 
 	// prepare data structure
 	responseData := map[string]interface{}{statusKey: "ok"}
-	responseData["content"] = server.Content
-	responseData["groups"] = server.groupsList
+	responseData["content"] = server.getContent()
+	responseData["groups"] = groupsList
 
 	err := responses.SendOK(writer, responseData)
 	if err != nil {
@@ -265,7 +335,12 @@ func (server *HTTPServer) serveContent(writer http.ResponseWriter, request *http
 }
 */
 
-func (server *HTTPServer) initGroupList() {
+// getGroupList returns list of all groups. The list is constructed from map
+// of groups when called for the first time.
+func (server *HTTPServer) getGroupList() []groups.Group {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
 	// let's mimick Content Service behaviour preciselly
 	if server.groupsList == nil {
 		log.Info().Msg("Initializing group list for the first time")
@@ -277,15 +352,21 @@ func (server *HTTPServer) initGroupList() {
 	}
 
 	log.Info().Int("items", len(server.groupsList)).Msg("Group list")
+	return server.groupsList
+}
+
+// getContent returns current rule content
+func (server *HTTPServer) getContent() []content.RuleContent {
+	return server.Storage.GetRuleContent()
 }
 
 // listOfGroups returns the list of defined groups
 func (server *HTTPServer) listOfGroups(writer http.ResponseWriter, _ *http.Request) {
 	log.Info().Msg("List of groups handler")
 
-	server.initGroupList()
+	groupsList := server.getGroupList()
 
-	err := responses.SendOK(writer, responses.BuildOkResponseWithData("groups", server.groupsList))
+	err := responses.SendOK(writer, responses.BuildOkResponseWithData("groups", groupsList))
 	if err != nil {
 		log.Error().Err(err).Msg("List of groups handler")
 		handleServerError(err)
@@ -313,6 +394,10 @@ func (server *HTTPServer) listOfClustersForOrganization(writer http.ResponseWrit
 		return
 	}
 
+	if !checkOrgPermissions(writer, request, organizationID) {
+		return
+	}
+
 	clusters, err := server.Storage.ListOfClustersForOrg(organizationID)
 	if err != nil {
 		log.Error().Err(err).Msg("Unable to get list of clusters")
@@ -356,6 +441,13 @@ func (server *HTTPServer) readReportForCluster(writer http.ResponseWriter, reque
 		handleServerError(err)
 		return
 	}
+
+	report, err = server.processReport(clusterName, readReportOptions(request), report)
+	if err != nil {
+		log.Error().Err(err).Msg(unableToProcessReportErrorMessage)
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 	writer.Header().Set(contentType, appJSON)
 
 	r := []byte(report)
@@ -384,16 +476,38 @@ func (server *HTTPServer) readReportForAllClustersInOrg(writer http.ResponseWrit
 	organizationID, err := readOrganizationID(writer, request)
 
 	if err != nil {
-		// everything has been handled already
+		sendImproperParameter(writer, errors.New("invalid organization ID"))
 		return
 	}
 	log.Info().Int("OrgID", int(organizationID)).Msg("Organization ID to get list of results")
 
+	if !checkOrgPermissions(writer, request, organizationID) {
+		return
+	}
+
+	clusters, err := server.Storage.ListOfClustersForOrg(organizationID)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to get list of clusters")
+		sendStorageError(writer, err)
+		return
+	}
+
 	var generatedReports ClusterReports
 	generatedReports.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
 
+	generatedReports.ClusterList = make([]types.ClusterName, 0, len(clusters))
+	generatedReports.Errors = make([]types.ClusterName, 0)
 	generatedReports.Reports = make(map[types.ClusterName]interface{})
 
+	options := readReportOptions(request)
+	for _, clusterName := range clusters {
+		reportStr, err := server.Storage.ReadReportForOrganizationAndCluster(organizationID, clusterName)
+		server.addClusterReport(&generatedReports, clusterName, reportStr, err, options)
+	}
+
+	// server response has JSON format for this endpoint
+	writer.Header().Set(contentType, appJSON)
+
 	bytes, err := json.MarshalIndent(generatedReports, "", "\t")
 	if err != nil {
 		log.Error().Err(err).Msg(responseDataError)
@@ -405,6 +519,35 @@ func (server *HTTPServer) readReportForAllClustersInOrg(writer http.ResponseWrit
 	}
 }
 
+// addClusterReport processes report read for given cluster and adds it into
+// generated reports. Cluster is added into list of errors when the report
+// can not be read or processed.
+func (server *HTTPServer) addClusterReport(
+	generatedReports *ClusterReports, clusterName types.ClusterName,
+	reportStr types.ClusterReport, err error, options reportOptions,
+) {
+	if err != nil {
+		log.Error().Err(err).Msg(unableToReadReportErrorMessage)
+		generatedReports.Errors = append(generatedReports.Errors, clusterName)
+		return
+	}
+	reportStr, err = server.processReport(clusterName, options, reportStr)
+	if err != nil {
+		log.Error().Err(err).Msg(unableToProcessReportErrorMessage)
+		generatedReports.Errors = append(generatedReports.Errors, clusterName)
+		return
+	}
+	var report interface{}
+	err = json.Unmarshal([]byte(reportStr), &report)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to unmarshal report for cluster")
+		generatedReports.Errors = append(generatedReports.Errors, clusterName)
+		return
+	}
+	generatedReports.ClusterList = append(generatedReports.ClusterList, clusterName)
+	generatedReports.Reports[clusterName] = report
+}
+
 func (server *HTTPServer) readReportForClusters(writer http.ResponseWriter, request *http.Request) {
 	var clusterList ClusterList
 	var generatedReports ClusterReports
@@ -432,26 +575,13 @@ func (server *HTTPServer) readReportForClusters(writer http.ResponseWriter, requ
 	writer.Header().Set(contentType, appJSON)
 
 	// construct reports for all clusters in a list
+	options := readReportOptions(request)
 	for _, clusterName := range clusterList.Clusters {
 		log.Info().Str("cluster name", clusterName).Msg("result for cluster")
 		clusterName := types.ClusterName(clusterName)
 		reportStr, err := server.Storage.ReadReportForCluster(clusterName)
-		if err != nil {
-			log.Error().Err(err).Msg(unableToReadReportErrorMessage)
-			generatedReports.Errors = append(generatedReports.Errors, clusterName)
-			// if error happen, simply go to the next cluster
-			continue
-		}
-		var report interface{}
-		err = json.Unmarshal([]byte(reportStr), &report)
-		if err != nil {
-			log.Error().Err(err).Msg("Unable to unmarshal report for cluster")
-			generatedReports.Errors = append(generatedReports.Errors, clusterName)
-			// if error happen, simply go to the next cluster
-			continue
-		}
-		generatedReports.ClusterList = append(generatedReports.ClusterList, clusterName)
-		generatedReports.Reports[clusterName] = report
+		// if error happen, simply go to the next cluster
+		server.addClusterReport(&generatedReports, clusterName, reportStr, err, options)
 	}
 
 	// try to serialize all reports
@@ -483,6 +613,10 @@ func (server *HTTPServer) readReportForOrganizationAndCluster(writer http.Respon
 		return
 	}
 
+	if !checkOrgPermissions(writer, request, organizationID) {
+		return
+	}
+
 	report, err := server.Storage.ReadReportForOrganizationAndCluster(organizationID, clusterName)
 	if err != nil {
 		writer.WriteHeader(http.StatusNotFound)
@@ -491,6 +625,13 @@ func (server *HTTPServer) readReportForOrganizationAndCluster(writer http.Respon
 		return
 	}
 
+	report, err = server.processReport(clusterName, readReportOptions(request), report)
+	if err != nil {
+		log.Error().Err(err).Msg(unableToProcessReportErrorMessage)
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	r := []byte(report)
 	// #nosec G705 -- Content-Type is set to application/json, no XSS risk in JSON API responses
 	_, err = writer.Write(r)
@@ -548,6 +689,7 @@ type HittingClustersMetadata struct {
 type HittingClusters struct {
 	Metadata    HittingClustersMetadata `json:"meta"`
 	ClusterList []types.ClusterName     `json:"data"`
+	AckedList   []types.ClusterName     `json:"acked,omitempty"`
 }
 
 // ruleClusterDetailEndpoint method implements endpoint that should return a list of all the clusters IDs affected by this rule
@@ -581,6 +723,22 @@ func (server *HTTPServer) ruleClusterDetailEndpoint(writer http.ResponseWriter,
 	clusters := readClustersHittingRule(component, errorKey)
 	log.Info().Int("cluster count", len(clusters)).Msg("Clusters hitting the rule")
 
+	// clusters with acked rule are omitted unless requested
+	includeAcked := readIncludeAcked(request)
+	ackedClusters := make([]types.ClusterName, 0)
+	visibleClusters := make([]types.ClusterName, 0, len(clusters))
+	ruleKey := ackedRuleKey(string(component), string(errorKey))
+	for _, cluster := range clusters {
+		if _, acked := server.ackedRulesForCluster(cluster)[ruleKey]; acked {
+			ackedClusters = append(ackedClusters, cluster)
+			if !includeAcked {
+				continue
+			}
+		}
+		visibleClusters = append(visibleClusters, cluster)
+	}
+	clusters = visibleClusters
+
 	// prepare response
 	writer.Header().Set(contentType, appJSON)
 	var hittingClusters HittingClusters
@@ -593,6 +751,9 @@ func (server *HTTPServer) ruleClusterDetailEndpoint(writer http.ResponseWriter,
 
 	// second fill-in list of clusters
 	hittingClusters.ClusterList = clusters
+	if includeAcked {
+		hittingClusters.AckedList = ackedClusters
+	}
 
 	bytes, err := json.MarshalIndent(hittingClusters, "", "\t")
 	if err != nil {
@@ -613,26 +774,28 @@ func logRequestID(requestID types.RequestID) {
 	log.Info().Str("request_id", string(requestID)).Msg(requestParameter)
 }
 
-// RequestStatus contains description about one request ID
+// RequestStatus contains description about one request ID. Processed
+// timestamp is set for already processed requests only.
 type RequestStatus struct {
 	RequestID string `json:"requestID"`
 	Valid     bool   `json:"valid"`
+	Status    string `json:"status"`
 	Received  string `json:"received"`
-	Processed string `json:"processed"`
+	Processed string `json:"processed,omitempty"`
 }
 
-func constructRequestsList(requestIDs []types.RequestID) []RequestStatus {
-	states := make([]RequestStatus, len(requestIDs))
+func constructRequestsList(requests []types.RequestInfo) []RequestStatus {
+	states := make([]RequestStatus, len(requests))
 
-	for i := range requestIDs {
-		states[i].RequestID = string(requestIDs[i])
+	for i, request := range requests {
+		states[i].RequestID = string(request.RequestID)
 		states[i].Valid = true
+		states[i].Status = request.Status
+		states[i].Received = request.Received.UTC().Format(time.RFC3339Nano)
 
-		received := time.Date(2000, time.November, 1, 1, 0, 0, 999, time.UTC).Format(time.RFC3339Nano)
-		states[i].Received = received
-
-		processed := time.Now().UTC().Format(time.RFC3339Nano)
-		states[i].Processed = processed
+		if request.Status == StatusProcessed {
+			states[i].Processed = request.Processed.UTC().Format(time.RFC3339Nano)
+		}
 	}
 	return states
 }
@@ -650,7 +813,7 @@ func (server *HTTPServer) readListOfRequestIDs(writer http.ResponseWriter, reque
 	}
 	logClusterName(clusterName)
 
-	requestIDs, found := data.RequestIDs[clusterName]
+	requests, found := server.Storage.ListOfRequestsForCluster(clusterName)
 	if !found {
 		err := responses.SendNotFound(writer, requestsForClusterNotFound)
 		if err != nil {
@@ -662,7 +825,7 @@ func (server *HTTPServer) readListOfRequestIDs(writer http.ResponseWriter, reque
 	// prepare data structure
 	responseData := map[string]interface{}{statusKey: "ok"}
 	responseData["cluster"] = string(clusterName)
-	responseData["requests"] = constructRequestsList(requestIDs)
+	responseData["requests"] = constructRequestsList(requests)
 
 	err = responses.SendOK(writer, responseData)
 	if err != nil {
@@ -700,22 +863,22 @@ func logRequestIDs(message string, requestList RequestList) {
 	}
 }
 
-func filterRequestIDs(requestIDs, requestList RequestList) RequestList {
+func filterRequests(requests []types.RequestInfo, requestList RequestList) []types.RequestInfo {
 	// filter request IDs using the rather inneficient way!
-	var filteredIDs RequestList
-	for _, requestID := range requestIDs {
+	var filtered []types.RequestInfo
+	for _, request := range requests {
 		found := false
 		for _, wantedID := range requestList {
-			if requestID == wantedID {
+			if request.RequestID == wantedID {
 				found = true
 				break
 			}
 		}
 		if found {
-			filteredIDs = append(filteredIDs, requestID)
+			filtered = append(filtered, request)
 		}
 	}
-	return filteredIDs
+	return filtered
 }
 
 // readListOfRequestIDsPostVariant method implements endpoint that should return a list of
@@ -738,7 +901,7 @@ func (server *HTTPServer) readListOfRequestIDsPostVariant(writer http.ResponseWr
 	}
 	logClusterName(clusterName)
 
-	requestIDs, found := data.RequestIDs[clusterName]
+	requests, found := server.Storage.ListOfRequestsForCluster(clusterName)
 	if !found {
 		err := responses.SendNotFound(writer, requestsForClusterNotFound)
 		if err != nil {
@@ -747,13 +910,13 @@ func (server *HTTPServer) readListOfRequestIDsPostVariant(writer http.ResponseWr
 		return
 	}
 
-	filteredIDs := filterRequestIDs(requestIDs, requestList)
-	logRequestIDs("Filtered IDs", filteredIDs)
+	filtered := filterRequests(requests, requestList)
+	log.Info().Int("count", len(filtered)).Msg("Filtered IDs")
 
 	// prepare data structure
 	responseData := map[string]interface{}{statusKey: "ok"}
 	responseData["cluster"] = string(clusterName)
-	responseData["requests"] = constructRequestsList(filteredIDs)
+	responseData["requests"] = constructRequestsList(filtered)
 
 	err = responses.SendOK(writer, responseData)
 	if err != nil {
@@ -762,8 +925,8 @@ func (server *HTTPServer) readListOfRequestIDsPostVariant(writer http.ResponseWr
 }
 
 // readStatusOfRequestID method implements endpoint that should return a status
-// for given request ID. Currently the status is set to "processed" or
-// "unknown" because we won't have information about "in-between" states.
+// for given request ID. The status is "received", "processing", "processed",
+// or "unknown" for request IDs that are not known or that have expired.
 func (server *HTTPServer) readStatusOfRequestID(writer http.ResponseWriter, request *http.Request) {
 	clusterName, err := readClusterName(writer, request)
 	if err != nil {
@@ -785,7 +948,7 @@ func (server *HTTPServer) readStatusOfRequestID(writer http.ResponseWriter, requ
 	}
 	logRequestID(requestID)
 
-	requestIDs, found := data.RequestIDs[clusterName]
+	requestInfo, found := server.Storage.GetRequestForCluster(clusterName, requestID)
 	if !found {
 		err := responses.SendNotFound(writer, requestsForClusterNotFound)
 		if err != nil {
@@ -798,16 +961,7 @@ func (server *HTTPServer) readStatusOfRequestID(writer http.ResponseWriter, requ
 	responseData := map[string]interface{}{}
 	responseData["cluster"] = string(clusterName)
 	responseData["requestID"] = requestID
-	responseData[statusKey] = "unknown"
-
-	// try to find the required request in requests IDs
-	for _, storedRequestID := range requestIDs {
-		if storedRequestID == requestID {
-			// update data structure
-			responseData[statusKey] = StatusProcessed
-			break
-		}
-	}
+	responseData[statusKey] = requestInfo.Status
 
 	// send response back to user
 	err = responses.SendOK(writer, responseData)
@@ -839,7 +993,7 @@ func (server *HTTPServer) readRuleHitsForRequestID(writer http.ResponseWriter, r
 	}
 	logRequestID(requestID)
 
-	_, found := data.RequestIDs[clusterName]
+	requestInfo, found := server.Storage.GetRequestForCluster(clusterName, requestID)
 	if !found {
 		err := responses.SendNotFound(writer, requestsForClusterNotFound)
 		if err != nil {
@@ -852,9 +1006,9 @@ func (server *HTTPServer) readRuleHitsForRequestID(writer http.ResponseWriter, r
 	var responseData types.SimplifiedReport
 	responseData.Cluster = string(clusterName)
 	responseData.RequestID = string(requestID)
-	responseData.Status = StatusProcessed
-	// can be nil
-	responseData.RuleHits = data.SimplifiedRuleHits[clusterName][requestID]
+	responseData.Status = requestInfo.Status
+	// rule hits are available for processed requests only, can be nil
+	responseData.RuleHits = requestInfo.RuleHits
 
 	bytes, err := json.MarshalIndent(responseData, "", "\t")
 	if err != nil {
@@ -0,0 +1,202 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// JWT bearer tokens signed by HMAC-SHA256 (HS256). Tokens are verified
+// against the key stored in local file. Tokens for testing purposes can be
+// minted by the mint-token command.
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// AuthorizationHeader is name of HTTP header that contains bearer token
+const AuthorizationHeader = "Authorization"
+
+// bearerPrefix is prefix of authorization header value with bearer token
+const bearerPrefix = "Bearer "
+
+// jwtAlgorithm is the only supported signing algorithm
+const jwtAlgorithm = "HS256"
+
+// jwtHeader represents header part of JWT token
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+// jwtClaims represents claims stored in JWT token
+type jwtClaims struct {
+	AccountNumber string `json:"account_number,omitempty"`
+	OrgID         string `json:"org_id"`
+	UserID        string `json:"user_id,omitempty"`
+	IssuedAt      int64  `json:"iat"`
+	ExpiresAt     int64  `json:"exp,omitempty"`
+}
+
+// ReadJWTKey reads key used to sign and verify JWT tokens from given file.
+// No key is shipped with the service, so the file needs to be configured.
+func ReadJWTKey(path string) ([]byte, error) {
+	if path == "" {
+		return nil, errors.New("JWT key file is not configured")
+	}
+
+	// #nosec G304
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key = bytes.TrimSpace(key)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("JWT key file '%s' is empty", path)
+	}
+	return key, nil
+}
+
+// loadJWTKey reads JWT key from file specified in configuration when JWT
+// authentication is enabled
+func (server *HTTPServer) loadJWTKey() error {
+	if server.Config.AuthType != AuthTypeJWT {
+		return nil
+	}
+
+	key, err := ReadJWTKey(server.Config.JWTKeyFile)
+	if err != nil {
+		return err
+	}
+	server.jwtKey = key
+	return nil
+}
+
+// encodeSegment encodes one part of JWT token
+func encodeSegment(value interface{}) (string, error) {
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(serialized), nil
+}
+
+// sign computes signature of header and claims parts of JWT token
+func sign(key []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+// MintToken creates JWT token with given identity signed by given key. Token
+// never expires when validity is zero.
+func MintToken(key []byte, identity types.Identity, validity time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwtClaims{
+		AccountNumber: identity.AccountNumber,
+		OrgID:         fmt.Sprint(identity.OrgID),
+		UserID:        string(identity.UserID),
+		IssuedAt:      now.Unix(),
+	}
+	if validity > 0 {
+		claims.ExpiresAt = now.Add(validity).Unix()
+	}
+
+	header, err := encodeSegment(jwtHeader{Algorithm: jwtAlgorithm, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := header + "." + payload
+	signature := base64.RawURLEncoding.EncodeToString(sign(key, signingInput))
+	return signingInput + "." + signature, nil
+}
+
+// verifyToken verifies signature and expiration of JWT token and returns
+// identity stored in its claims
+func verifyToken(key []byte, token string) (types.Identity, error) {
+	var identity types.Identity
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return identity, fmt.Errorf("%w: token has improper format", errMissingIdentity)
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return identity, fmt.Errorf("%w: token header is not encoded properly", errMissingIdentity)
+	}
+	var header jwtHeader
+	err = json.Unmarshal(headerBytes, &header)
+	if err != nil || header.Algorithm != jwtAlgorithm {
+		return identity, fmt.Errorf("%w: unsupported token algorithm", errMissingIdentity)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(key, parts[0]+"."+parts[1])) {
+		return identity, fmt.Errorf("%w: invalid token signature", errMissingIdentity)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return identity, fmt.Errorf("token claims are not encoded properly: %w", err)
+	}
+	var claims jwtClaims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return identity, fmt.Errorf("token claims have improper format: %w", err)
+	}
+
+	if claims.ExpiresAt != 0 && time.Now().Unix() >= claims.ExpiresAt {
+		return identity, fmt.Errorf("%w: token has expired", errMissingIdentity)
+	}
+
+	if claims.OrgID == "" {
+		return identity, errors.New("organization ID is not set in token")
+	}
+	identity.OrgID, err = parseOrgID(claims.OrgID)
+	if err != nil {
+		return identity, err
+	}
+	identity.AccountNumber = claims.AccountNumber
+	identity.UserID = types.UserID(claims.UserID)
+
+	return identity, nil
+}
+
+// readJWTIdentity reads identity from bearer token stored in Authorization
+// header
+func (server *HTTPServer) readJWTIdentity(request *http.Request) (types.Identity, error) {
+	header := request.Header.Get(AuthorizationHeader)
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return types.Identity{}, fmt.Errorf("%w: missing bearer token", errMissingIdentity)
+	}
+	return verifyToken(server.jwtKey, strings.TrimPrefix(header, bearerPrefix))
+}
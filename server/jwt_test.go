@@ -0,0 +1,72 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// TestMintAndVerifyToken checks that minted token can be verified and that
+// identity is read from its claims
+func TestMintAndVerifyToken(t *testing.T) {
+	key := []byte("test-key")
+	identity := types.Identity{AccountNumber: "6278", OrgID: 11789772, UserID: "tester"}
+
+	token, err := server.MintToken(key, identity, time.Hour)
+	assert.NoError(t, err)
+
+	verified, err := server.VerifyToken(key, token)
+	assert.NoError(t, err)
+	assert.Equal(t, identity, verified)
+
+	// token signed by different key
+	_, err = server.VerifyToken([]byte("other-key"), token)
+	assert.Error(t, err)
+
+	// token with modified claims
+	parts := strings.Split(token, ".")
+	_, err = server.VerifyToken(key, parts[0]+"."+parts[0]+"."+parts[2])
+	assert.Error(t, err)
+
+	// expired token
+	token, err = server.MintToken(key, identity, time.Nanosecond)
+	assert.NoError(t, err)
+	_, err = server.VerifyToken(key, token)
+	assert.Error(t, err)
+}
+
+// TestReadJWTKey checks that key is read from configured file only
+func TestReadJWTKey(t *testing.T) {
+	_, err := server.ReadJWTKey("")
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwt.key")
+	assert.NoError(t, os.WriteFile(path, []byte("test-key\n"), 0o600))
+
+	key, err := server.ReadJWTKey(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("test-key"), key)
+}
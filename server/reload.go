@@ -0,0 +1,94 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// Reloading of mock data, rule content and groups without the need to
+// restart the whole service.
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/RedHatInsights/insights-operator-utils/responses"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/groups"
+)
+
+// DataLoader is a function that reads rule groups and rule content from
+// their sources (usually from files specified in configuration)
+type DataLoader func() (map[string]groups.Group, []content.RuleContent, error)
+
+// Reload method re-reads rule groups, rule content and all mock data stored
+// in storage. New data replace the current ones only when all of them were
+// read successfully. Concurrent reloads are serialized and all data are
+// replaced at once, so changes made via debug endpoints are dropped.
+func (server *HTTPServer) Reload() error {
+	if server.Loader == nil {
+		return errors.New("data loader is not set")
+	}
+
+	server.reloadLock.Lock()
+	defer server.reloadLock.Unlock()
+
+	// read everything before the current data are replaced
+	ruleGroups, ruleContent, err := server.Loader()
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to read groups or rule content")
+		return err
+	}
+
+	// groups are replaced while mock data and rule content are replaced in
+	// storage, so readers never see groups that do not match rule content
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	err = server.Storage.Reload(ruleContent)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to reload mock data")
+		return err
+	}
+
+	server.Groups = ruleGroups
+
+	// list of groups will be constructed again on demand
+	server.groupsList = nil
+
+	log.Info().
+		Int("groups", len(ruleGroups)).
+		Int("content", len(ruleContent)).
+		Msg("Groups and rule content reloaded")
+	return nil
+}
+
+// reload method implements the debug endpoint to reload all data
+func (server *HTTPServer) reload(writer http.ResponseWriter, _ *http.Request) {
+	err := server.Reload()
+	if err != nil {
+		err = responses.SendInternalServerError(writer, err.Error())
+		if err != nil {
+			log.Error().Err(err).Msg(responseDataError)
+		}
+		return
+	}
+
+	err = responses.SendOK(writer, responses.BuildOkResponse())
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// Reports are stored as they were read from mock data directory. Data
+// changed by users (votes, disabled rules, acks etc.) are applied to reports
+// just before they are sent to client. Missing attributes of rule hits are filled
+// from rule content.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// GetDisabledParam is name of query parameter that allows to include
+// disabled rules in reports
+const GetDisabledParam = "get_disabled"
+
+// GetAckedParam is name of query parameter that allows to include acked
+// rules in reports and in lists of clusters hitting given rule
+const GetAckedParam = "get_acked"
+
+// reportOptions contains all settings that affect how report is presented
+// to user
+type reportOptions struct {
+	userID          types.UserID
+	includeDisabled bool
+	includeAcked    bool
+}
+
+// readReportOptions retrieves report options from request
+func readReportOptions(request *http.Request) reportOptions {
+	return reportOptions{
+		userID:          readUserID(request),
+		includeDisabled: request.URL.Query().Get(GetDisabledParam) == "true",
+		includeAcked:    readIncludeAcked(request),
+	}
+}
+
+// readIncludeAcked checks if acked rules should be included in response
+func readIncludeAcked(request *http.Request) bool {
+	return request.URL.Query().Get(GetAckedParam) == "true"
+}
+
+// parsedReport represents report with rule hits that can be modified
+type parsedReport struct {
+	report        map[string]interface{}
+	ruleHits      []map[string]interface{}
+	originalCount int
+}
+
+// parseReport parses report so its rule hits can be modified. All other
+// attributes are kept as they are.
+func parseReport(report types.ClusterReport) (parsedReport, error) {
+	var parsed parsedReport
+
+	// numbers need to be kept as they are
+	decoder := json.NewDecoder(bytes.NewReader([]byte(report)))
+	decoder.UseNumber()
+	err := decoder.Decode(&parsed.report)
+	if err != nil {
+		return parsed, err
+	}
+
+	reportPart, ok := parsed.report["report"].(map[string]interface{})
+	if !ok {
+		return parsed, errors.New("report attribute is missing")
+	}
+
+	ruleHits, ok := reportPart["data"].([]interface{})
+	if !ok {
+		return parsed, errors.New("data attribute is missing in report")
+	}
+
+	for _, ruleHit := range ruleHits {
+		hit, ok := ruleHit.(map[string]interface{})
+		if !ok {
+			return parsed, errors.New("rule hit is not an object")
+		}
+		parsed.ruleHits = append(parsed.ruleHits, hit)
+	}
+	parsed.originalCount = len(parsed.ruleHits)
+	return parsed, nil
+}
+
+// serialize stores modified rule hits back into report and serializes it
+func (parsed parsedReport) serialize() (types.ClusterReport, error) {
+	ruleHits := make([]interface{}, len(parsed.ruleHits))
+	for i, hit := range parsed.ruleHits {
+		ruleHits[i] = hit
+	}
+
+	reportPart := parsed.report["report"].(map[string]interface{})
+	reportPart["data"] = ruleHits
+
+	// count stored in mock data is kept when no rule hit has been removed
+	if meta, ok := reportPart["meta"].(map[string]interface{}); ok && len(ruleHits) != parsed.originalCount {
+		meta["count"] = len(ruleHits)
+	}
+
+	serialized, err := json.MarshalIndent(parsed.report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return types.ClusterReport(serialized), nil
+}
+
+// isEmptyAttribute checks if the attribute of rule hit (or the value from
+// rule content) is missing or empty
+func isEmptyAttribute(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case json.Number:
+		return v.String() == "0"
+	case int:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// fillRuleHit fills missing or empty attributes of rule hit from rule
+// content. It returns true when any attribute has been changed.
+func (server *HTTPServer) fillRuleHit(hit map[string]interface{}) bool {
+	ruleID, _ := hit["rule_id"].(string)
+	details, _ := hit["details"].(map[string]interface{})
+	errorKey, _ := details["error_key"].(string)
+
+	rule, err := server.Storage.GetRuleWithContent(types.RuleID(ruleID), types.ErrorKey(errorKey))
+	if err != nil {
+		// rule hits without content are served as they are
+		return false
+	}
+
+	changed := false
+	fill := func(attribute string, value interface{}) {
+		if isEmptyAttribute(hit[attribute]) && !isEmptyAttribute(value) {
+			hit[attribute] = value
+			changed = true
+		}
+	}
+
+	fill("description", rule.Description)
+	fill("reason", rule.Reason)
+	fill("resolution", rule.Resolution)
+	fill("total_risk", rule.TotalRisk)
+	fill("tags", rule.Tags)
+	if !rule.PublishDate.IsZero() {
+		fill("created_at", rule.PublishDate.UTC().Format(time.RFC3339))
+	}
+
+	return changed
+}
+
+// processReport applies data changed by user and rule content to report for
+// given cluster. The original report is returned when there's nothing to
+// change.
+func (server *HTTPServer) processReport(
+	clusterName types.ClusterName, options reportOptions, report types.ClusterReport,
+) (types.ClusterReport, error) {
+	votes := server.Storage.GetUserVotesForCluster(clusterName, options.userID)
+
+	disabledRules := make(map[types.RuleID]struct{})
+	for _, disabledRule := range server.Storage.ListOfDisabledRulesForCluster(clusterName) {
+		disabledRules[disabledRule.RuleID] = struct{}{}
+	}
+
+	ackedRules := server.ackedRulesForCluster(clusterName)
+
+	if len(votes) == 0 && len(disabledRules) == 0 && len(ackedRules) == 0 &&
+		!options.includeAcked && len(server.getContent()) == 0 {
+		return report, nil
+	}
+
+	parsed, err := parseReport(report)
+	if err != nil {
+		return report, err
+	}
+
+	changed := false
+	ruleHits := make([]map[string]interface{}, 0, len(parsed.ruleHits))
+	for _, hit := range parsed.ruleHits {
+		ruleID, _ := hit["rule_id"].(string)
+
+		if _, disabled := disabledRules[types.RuleID(ruleID)]; disabled {
+			changed = true
+			if !options.includeDisabled {
+				continue
+			}
+			hit["disabled"] = true
+		}
+
+		// acked rules are marked only when they are requested
+		details, _ := hit["details"].(map[string]interface{})
+		errorKey, _ := details["error_key"].(string)
+		_, acked := ackedRules[ackedRuleKey(ruleID, errorKey)]
+		if acked && !options.includeAcked {
+			changed = true
+			continue
+		}
+		if options.includeAcked {
+			hit["acked"] = acked
+			changed = true
+		}
+
+		if vote, found := votes[types.RuleID(ruleID)]; found {
+			hit["user_vote"] = vote
+			changed = true
+		}
+
+		// rule hits injected at runtime might contain just rule ID and
+		// error key
+		if _, found := hit["user_vote"]; !found {
+			hit["user_vote"] = types.UserVoteNone
+			changed = true
+		}
+		if _, found := hit["disabled"]; !found {
+			hit["disabled"] = false
+			changed = true
+		}
+
+		if server.fillRuleHit(hit) {
+			changed = true
+		}
+		ruleHits = append(ruleHits, hit)
+	}
+
+	if !changed {
+		return report, nil
+	}
+	parsed.ruleHits = ruleHits
+
+	return parsed.serialize()
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
+)
+
+// readRuleHits reads report for given cluster and returns its rule hits
+func readRuleHits(t *testing.T, handler http.Handler, url string) []map[string]interface{} {
+	var response struct {
+		Report struct {
+			Data []map[string]interface{} `json:"data"`
+		} `json:"report"`
+	}
+
+	request := httptest.NewRequest(http.MethodGet, url, http.NoBody)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	return response.Report.Data
+}
+
+// TestAckedRulesInReport checks that rules acked in organization that owns
+// the cluster are omitted from report unless requested
+func TestAckedRulesInReport(t *testing.T) {
+	const url = "/api/report/11789772/34c3ecc5-624a-49a5-bab8-4fdc5e51a266"
+
+	handler, storageInstance := newTestHandler(t, server.Configuration{APIPrefix: "/api/"}, nil)
+
+	// four out of seven rules are acked in fixture file
+	ruleHits := readRuleHits(t, handler, url)
+	assert.Len(t, ruleHits, 3)
+	for _, hit := range ruleHits {
+		assert.NotContains(t, hit, "acked")
+	}
+
+	ruleHits = readRuleHits(t, handler, url+"?get_acked=true")
+	assert.Len(t, ruleHits, 7)
+	acked := 0
+	for _, hit := range ruleHits {
+		if hit["acked"] == true {
+			acked++
+		}
+	}
+	assert.Equal(t, 4, acked)
+
+	// acks made in other organization are not applied
+	_, created := storageInstance.AddAck(1, "ccx_rules_ocp.external.rules.node_installer_degraded.report|NODE_INSTALLER_DEGRADED", "", "")
+	assert.True(t, created)
+	assert.Len(t, readRuleHits(t, handler, url), 3)
+}
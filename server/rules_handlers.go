@@ -0,0 +1,161 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// Handlers for endpoints that provide rule content and for debug endpoints
+// to change rule content at runtime. Changed rule content is dropped when
+// rule content is reloaded.
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/RedHatInsights/insights-operator-utils/responses"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
+)
+
+// getRule returns rule with content for given rule ID and error key
+func (server *HTTPServer) getRule(writer http.ResponseWriter, request *http.Request) {
+	ruleID, err := readRuleID(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	errorKey, err := readErrorKey(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	rule, err := server.Storage.GetRuleWithContent(ruleID, errorKey)
+	if err != nil {
+		log.Error().Err(err).Str("rule", string(ruleID)).Str("error key", string(errorKey)).Msg("Unable to get rule")
+		sendStorageError(writer, err)
+		return
+	}
+
+	err = responses.SendOK(writer, responses.BuildOkResponseWithData("report", rule))
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// sendRuleCreated sends response for successfully created rule or error key
+func sendRuleCreated(writer http.ResponseWriter) {
+	err := responses.SendCreated(writer, responses.BuildOkResponse())
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// createRule adds new rule into rule content or replaces the existing one
+func (server *HTTPServer) createRule(writer http.ResponseWriter, request *http.Request) {
+	ruleID, err := readRuleID(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	var ruleContent content.RuleContent
+	err = json.NewDecoder(request.Body).Decode(&ruleContent)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	server.Storage.AddRule(ruleID, ruleContent)
+	log.Info().Str("rule", string(ruleID)).Msg("Rule created")
+
+	sendRuleCreated(writer)
+}
+
+// deleteRule deletes rule from rule content
+func (server *HTTPServer) deleteRule(writer http.ResponseWriter, request *http.Request) {
+	ruleID, err := readRuleID(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	err = server.Storage.DeleteRule(ruleID)
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+	log.Info().Str("rule", string(ruleID)).Msg("Rule deleted")
+
+	sendOKStatus(writer)
+}
+
+// createRuleErrorKey adds new error key into existing rule or replaces the
+// existing error key
+func (server *HTTPServer) createRuleErrorKey(writer http.ResponseWriter, request *http.Request) {
+	ruleID, err := readRuleID(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	errorKey, err := readErrorKey(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	var errorKeyContent content.RuleErrorKeyContent
+	err = json.NewDecoder(request.Body).Decode(&errorKeyContent)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	err = server.Storage.AddRuleErrorKey(ruleID, errorKey, errorKeyContent)
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+	log.Info().Str("rule", string(ruleID)).Str("error key", string(errorKey)).Msg("Error key created")
+
+	sendRuleCreated(writer)
+}
+
+// deleteRuleErrorKey deletes error key from existing rule
+func (server *HTTPServer) deleteRuleErrorKey(writer http.ResponseWriter, request *http.Request) {
+	ruleID, err := readRuleID(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	errorKey, err := readErrorKey(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	err = server.Storage.DeleteRuleErrorKey(ruleID, errorKey)
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+	log.Info().Str("rule", string(ruleID)).Str("error key", string(errorKey)).Msg("Error key deleted")
+
+	sendOKStatus(writer)
+}
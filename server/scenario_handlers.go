@@ -0,0 +1,188 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// Debug endpoints that allow integration tests to change cluster reports and
+// assignment of clusters to organizations on the fly. All changes are kept
+// in memory only and they are dropped when mock data are reloaded.
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/RedHatInsights/insights-operator-utils/responses"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// readOrganizationIDs retrieves comma separated list of organization IDs
+// from request
+func readOrganizationIDs(request *http.Request) ([]types.OrgID, error) {
+	param, err := getRouterParam(request, "organizations")
+	if err != nil {
+		return nil, err
+	}
+
+	var orgIDs []types.OrgID
+	for _, value := range strings.Split(param, ",") {
+		orgID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || orgID == 0 {
+			return nil, fmt.Errorf("invalid organization ID: '%s'", value)
+		}
+		orgIDs = append(orgIDs, types.OrgID(orgID))
+	}
+	return orgIDs, nil
+}
+
+// readClusterNames retrieves comma separated list of cluster names from
+// request
+func readClusterNames(request *http.Request) ([]types.ClusterName, error) {
+	param, err := getRouterParam(request, "clusters")
+	if err != nil {
+		return nil, err
+	}
+
+	var clusterNames []types.ClusterName
+	for _, value := range strings.Split(param, ",") {
+		clusterName, err := ValidateClusterName(value)
+		if err != nil {
+			return nil, err
+		}
+		clusterNames = append(clusterNames, clusterName)
+	}
+	return clusterNames, nil
+}
+
+// sendOKStatus sends response with just "ok" status for successfully performed
+// change
+func sendOKStatus(writer http.ResponseWriter) {
+	err := responses.SendOK(writer, responses.BuildOkResponse())
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// writeReportForCluster stores report sent in request body for given cluster
+func (server *HTTPServer) writeReportForCluster(writer http.ResponseWriter, request *http.Request) {
+	clusterName, err := readClusterName(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	report, err := io.ReadAll(request.Body)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	err = server.Storage.WriteReportForCluster(clusterName, types.ClusterReport(report))
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+	sendOKStatus(writer)
+}
+
+// deleteReportForCluster deletes report for given cluster
+func (server *HTTPServer) deleteReportForCluster(writer http.ResponseWriter, request *http.Request) {
+	clusterName, err := readClusterName(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	err = server.Storage.DeleteReportForCluster(clusterName)
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+	sendOKStatus(writer)
+}
+
+// addClusterToOrganization assigns cluster to given organization
+func (server *HTTPServer) addClusterToOrganization(writer http.ResponseWriter, request *http.Request) {
+	organizationID, err := readOrganizationID(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, errors.New("invalid organization ID"))
+		return
+	}
+
+	clusterName, err := readClusterName(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	err = server.Storage.AddClusterToOrg(organizationID, clusterName)
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+	sendOKStatus(writer)
+}
+
+// removeClusterFromOrganization removes cluster from given organization
+func (server *HTTPServer) removeClusterFromOrganization(writer http.ResponseWriter, request *http.Request) {
+	organizationID, err := readOrganizationID(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, errors.New("invalid organization ID"))
+		return
+	}
+
+	clusterName, err := readClusterName(writer, request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	err = server.Storage.RemoveClusterFromOrg(organizationID, clusterName)
+	if err != nil {
+		sendStorageError(writer, err)
+		return
+	}
+	sendOKStatus(writer)
+}
+
+// deleteOrganizations deletes all organizations from comma separated list
+func (server *HTTPServer) deleteOrganizations(writer http.ResponseWriter, request *http.Request) {
+	orgIDs, err := readOrganizationIDs(request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	server.Storage.DeleteOrganizations(orgIDs)
+	sendOKStatus(writer)
+}
+
+// deleteClusters deletes all clusters from comma separated list
+func (server *HTTPServer) deleteClusters(writer http.ResponseWriter, request *http.Request) {
+	clusterNames, err := readClusterNames(request)
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+
+	server.Storage.DeleteClusters(clusterNames)
+	sendOKStatus(writer)
+}
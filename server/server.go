@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	// we just have to import this package in order to expose pprof interface in debug mode
@@ -31,7 +32,9 @@ import (
 	_ "net/http/pprof" // #nosec G108
 	"path/filepath"
 
+	httputils "github.com/RedHatInsights/insights-operator-utils/http"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
 
 	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
@@ -46,19 +49,23 @@ type HTTPServer struct {
 	Groups     map[string]groups.Group
 	Serv       *http.Server
 	groupsList []groups.Group
-	Content    []content.RuleContent
+	Loader     DataLoader
+	mutex      sync.RWMutex
+	reloadLock sync.Mutex
+	jwtKey     []byte
 }
 
-// New constructs new implementation of Server interface
+// New constructs new implementation of Server interface. Rule content is
+// stored in storage as it can be changed at runtime.
 func New(config Configuration,
 	storageInstance storage.Storage,
 	ruleGroups map[string]groups.Group,
 	ruleContents []content.RuleContent) *HTTPServer {
+	storageInstance.SetRuleContent(ruleContents)
 	return &HTTPServer{
 		Config:  config,
 		Storage: storageInstance,
 		Groups:  ruleGroups,
-		Content: ruleContents,
 	}
 }
 
@@ -66,6 +73,19 @@ func New(config Configuration,
 func (server *HTTPServer) Start() error {
 	address := server.Config.Address
 	log.Info().Msgf("Starting HTTP server at '%s'", address)
+
+	err := checkAuthType(server.Config.AuthType)
+	if err != nil {
+		log.Error().Err(err).Msg("Improper server configuration")
+		return err
+	}
+
+	err = server.loadJWTKey()
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to read JWT key")
+		return err
+	}
+
 	router := server.Initialize(address)
 	server.Serv = &http.Server{
 		Addr:              address,
@@ -75,7 +95,7 @@ func (server *HTTPServer) Start() error {
 
 	server.printAccessInfo()
 
-	err := server.Serv.ListenAndServe()
+	err = server.Serv.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		log.Error().Err(err).Msg("Unable to start HTTP/S server")
 		return err
@@ -109,6 +129,16 @@ func (server *HTTPServer) Initialize(address string) http.Handler {
 	log.Info().Msgf("Initializing HTTP server at '%s'", address)
 
 	router := mux.NewRouter().StrictSlash(true)
+	router.Use(httputils.LogRequest)
+
+	switch server.Config.AuthType {
+	case AuthTypeXRH:
+		log.Info().Msg("Identity is read from " + IdentityHeader + " header")
+		router.Use(server.authenticate)
+	case AuthTypeJWT:
+		log.Info().Msg("Identity is read from JWT bearer token")
+		router.Use(server.authenticate)
+	}
 
 	server.addEndpointsToRouter(router)
 
@@ -123,14 +153,25 @@ func (server *HTTPServer) Initialize(address string) http.Handler {
 	return router
 }
 
-func (server *HTTPServer) addEndpointsToRouter(router *mux.Router) {
+// apiPrefix returns API prefix that always ends with slash
+func (server *HTTPServer) apiPrefix() string {
 	apiPrefix := server.Config.APIPrefix
 	if !strings.HasSuffix(apiPrefix, "/") {
 		apiPrefix += "/"
 	}
+	return apiPrefix
+}
+
+// openAPIURL returns URL of OpenAPI specification file
+func (server *HTTPServer) openAPIURL() string {
+	return server.apiPrefix() + filepath.Base(server.Config.APISpecFile)
+}
+
+func (server *HTTPServer) addEndpointsToRouter(router *mux.Router) {
+	apiPrefix := server.apiPrefix()
 	log.Info().Msgf("API prefix is set to '%s'", apiPrefix)
 
-	openAPIURL := apiPrefix + filepath.Base(server.Config.APISpecFile)
+	openAPIURL := server.openAPIURL()
 
 	// common REST API endpoints
 	router.HandleFunc(apiPrefix+MainEndpoint, server.mainEndpoint).Methods(http.MethodGet)
@@ -146,6 +187,17 @@ func (server *HTTPServer) addEndpointsToRouter(router *mux.Router) {
 	router.HandleFunc(apiPrefix+ClustersEndpoint, server.readReportForClusters).Methods(http.MethodGet, http.MethodPost, http.MethodOptions)
 	router.HandleFunc(apiPrefix+ClustersInOrgEndpoint, server.readReportForAllClustersInOrg).Methods(http.MethodGet)
 	router.HandleFunc(apiPrefix+RuleClusterDetailEndpoint, server.ruleClusterDetailEndpoint).Methods(http.MethodGet)
+	router.HandleFunc(apiPrefix+RuleErrorKeyEndpoint, server.getRule).Methods(http.MethodGet, http.MethodOptions)
+
+	// votes on rules, please look into votes_handlers.go
+	router.HandleFunc(apiPrefix+LikeRuleEndpoint, server.likeRule).Methods(http.MethodPut, http.MethodOptions)
+	router.HandleFunc(apiPrefix+DislikeRuleEndpoint, server.dislikeRule).Methods(http.MethodPut, http.MethodOptions)
+	router.HandleFunc(apiPrefix+ResetVoteOnRuleEndpoint, server.resetVoteOnRule).Methods(http.MethodPut, http.MethodOptions)
+
+	// disabling rules for clusters, please look into disabled_rules_handlers.go
+	router.HandleFunc(apiPrefix+DisableRuleForClusterEndpoint, server.disableRuleForCluster).Methods(http.MethodPut, http.MethodOptions)
+	router.HandleFunc(apiPrefix+EnableRuleForClusterEndpoint, server.enableRuleForCluster).Methods(http.MethodPut, http.MethodOptions)
+	router.HandleFunc(apiPrefix+DisabledRulesForClusterEndpoint, server.listOfDisabledRulesForCluster).Methods(http.MethodGet)
 
 	// Endpoints to manipulate with simplified rule results stored
 	// independently under "tracker_id" identifier
@@ -153,6 +205,7 @@ func (server *HTTPServer) addEndpointsToRouter(router *mux.Router) {
 	router.HandleFunc(apiPrefix+ListAllRequestIDs, server.readListOfRequestIDsPostVariant).Methods(http.MethodPost)
 	router.HandleFunc(apiPrefix+StatusOfRequestID, server.readStatusOfRequestID).Methods(http.MethodGet)
 	router.HandleFunc(apiPrefix+RuleHitsForRequestID, server.readRuleHitsForRequestID).Methods(http.MethodGet)
+	router.HandleFunc(apiPrefix+TriggerGathering, server.triggerGathering).Methods(http.MethodPost)
 
 	// Acknowledgement-related endpoints. Please look into acks_handlers.go
 	// and acks_utils.go for more information about these endpoints
@@ -166,6 +219,7 @@ func (server *HTTPServer) addEndpointsToRouter(router *mux.Router) {
 	// Upgrade risks prediction endpoints. Please look into upgrade_risks_prediction.go
 	// for more information about this endpoint
 	router.HandleFunc(apiPrefix+UpgradeRisksPredictionEndpoint, server.upgradeRisksPrediction).Methods(http.MethodGet)
+	router.HandleFunc(apiPrefix+UpgradeRisksPredictionHistoryEndpoint, server.upgradeRisksPredictionHistory).Methods(http.MethodGet)
 	router.HandleFunc(apiPrefix+UpgradeRisksPredictionMultiClusterEndpoint, server.upgradeRisksPredictionMultiCluster).Methods(http.MethodPost)
 
 	// DVO-related endpoints:
@@ -178,18 +232,37 @@ func (server *HTTPServer) addEndpointsToRouter(router *mux.Router) {
 	router.HandleFunc(apiPrefix+AllDVONamespaces, server.allDVONamespaces).Methods(http.MethodGet)
 	router.HandleFunc(apiPrefix+DVONamespaceForCluster1, server.dvoNamespaceForCluster).Methods(http.MethodGet)
 	router.HandleFunc(apiPrefix+DVONamespaceForCluster2, server.dvoNamespaceForCluster).Methods(http.MethodGet)
+	router.HandleFunc(apiPrefix+DVONamespaceInfo, server.dvoNamespaceInfo).Methods(http.MethodGet)
+	router.HandleFunc(apiPrefix+DVONamespaceReports, server.dvoNamespaceReports).Methods(http.MethodGet)
 
 	// OpenAPI specs
 	router.HandleFunc(openAPIURL, server.serveAPISpecFile).Methods(http.MethodGet)
+
+	// Prometheus metrics
+	router.Handle(apiPrefix+MetricsEndpoint, promhttp.Handler()).Methods(http.MethodGet)
 }
 
 func (server *HTTPServer) addDebugEndpointsToRouter(router *mux.Router) {
-	apiPrefix := server.Config.APIPrefix
-	if !strings.HasSuffix(apiPrefix, "/") {
-		apiPrefix += "/"
-	}
+	apiPrefix := server.apiPrefix()
 
 	router.HandleFunc(apiPrefix+ExitEndpoint, server.exit).Methods(http.MethodPut)
+	router.HandleFunc(apiPrefix+ReloadEndpoint, server.reload).Methods(http.MethodPost)
+
+	router.HandleFunc(apiPrefix+GetVoteOnRuleEndpoint, server.getVoteOnRule).Methods(http.MethodGet)
+
+	// scenario control endpoints, please look into scenario_handlers.go
+	router.HandleFunc(apiPrefix+ReportForClusterEndpoint2, server.writeReportForCluster).Methods(http.MethodPut)
+	router.HandleFunc(apiPrefix+ReportForClusterEndpoint2, server.deleteReportForCluster).Methods(http.MethodDelete)
+	router.HandleFunc(apiPrefix+ClusterInOrganizationEndpoint, server.addClusterToOrganization).Methods(http.MethodPut)
+	router.HandleFunc(apiPrefix+ClusterInOrganizationEndpoint, server.removeClusterFromOrganization).Methods(http.MethodDelete)
+	router.HandleFunc(apiPrefix+DeleteOrganizationsEndpoint, server.deleteOrganizations).Methods(http.MethodDelete)
+	router.HandleFunc(apiPrefix+DeleteClustersEndpoint, server.deleteClusters).Methods(http.MethodDelete)
+
+	// rule content changes, please look into rules_handlers.go
+	router.HandleFunc(apiPrefix+RuleEndpoint, server.createRule).Methods(http.MethodPost)
+	router.HandleFunc(apiPrefix+RuleEndpoint, server.deleteRule).Methods(http.MethodDelete)
+	router.HandleFunc(apiPrefix+RuleErrorKeyEndpoint, server.createRuleErrorKey).Methods(http.MethodPost)
+	router.HandleFunc(apiPrefix+RuleErrorKeyEndpoint, server.deleteRuleErrorKey).Methods(http.MethodDelete)
 }
 
 /*
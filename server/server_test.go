@@ -15,3 +15,57 @@ limitations under the License.
 */
 
 package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/metrics"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
+)
+
+// newTestHandler constructs handler of all endpoints that serves mock data
+// stored in data directory. Storage is returned as well so tests can check
+// or prepare its state.
+func newTestHandler(t *testing.T, config server.Configuration, ruleContent []content.RuleContent) (
+	http.Handler, *storage.MemoryStorage,
+) {
+	storageInstance, err := storage.New("../data")
+	assert.NoError(t, err)
+
+	handler := server.New(config, storageInstance, nil, ruleContent).Initialize("")
+	return handler, storageInstance
+}
+
+// TestAPIMetrics checks that API metrics are updated for each request, that
+// route template is used as endpoint label, and that metrics are exposed
+func TestAPIMetrics(t *testing.T) {
+	const endpoint = "/api/report/{cluster}"
+
+	handler, _ := newTestHandler(t, server.Configuration{APIPrefix: "/api/"}, nil)
+
+	for _, cluster := range []string{
+		"34c3ecc5-624a-49a5-bab8-4fdc5e51a266",
+		"74ae54aa-6577-4e80-85e7-697cb646ff37",
+		"00000000-0000-0000-0000-000000000000",
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/api/report/"+cluster, http.NoBody)
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.APIRequests.WithLabelValues(endpoint)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.APIResponseStatusCodes.WithLabelValues("404", endpoint)))
+
+	request := httptest.NewRequest(http.MethodGet, "/api/metrics", http.NoBody)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `api_endpoints_requests{endpoint="`+endpoint+`"} 3`)
+}
@@ -17,33 +17,30 @@ limitations under the License.
 package server
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	httputils "github.com/RedHatInsights/insights-operator-utils/http"
 	"github.com/RedHatInsights/insights-operator-utils/responses"
 	"github.com/rs/zerolog/log"
 
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
 )
 
 const (
-	// ClusterOk is the cluster name for a OK response with no upgrade risks detected
-	ClusterOk = "00000001-624a-49a5-bab8-4fdc5e51a266"
-	// ClusterOkFailUpgrade is the cluster name for a OK response with upgrade risks detected
-	ClusterOkFailUpgrade = "00000003-eeee-eeee-eeee-000000000001"
-	// ClusterManaged is the cluster name for the response when a cluster in "managed"
-	ClusterManaged = "6cab9726-c2be-438e-af11-db846a678abb"
-	// ClusterNoAMS is the cluster name for the response when the AMS API is not available
-	ClusterNoAMS = "c60ba611-6af4-4d62-9b9e-36344da5e7bc"
-	// ClusterUnavailable is the cluster name for the response when the Upgrade risks prediction service is unavailable
-	ClusterUnavailable = "897ec1a1-4679-4122-aacb-f0ae9f9e1a5f"
-	// ClusterNoData is the cluster name for the response when the Upgrade risks prediction service returns a 404
-	ClusterNoData = "234ec1a1-4679-4122-aacb-f0ae9f9e1a56"
-
-	clusterHasNoData  = "No data for the cluster"
-	statusOk          = "ok"
-	reasonNotExpected = "NotExpected"
+	clusterHasNoData = "No data for the cluster"
+	statusOk         = "ok"
+)
+
+// Query parameters accepted by upgrade risks prediction history endpoint.
+// Both parameters are optional and they need to be in RFC3339 format.
+const (
+	FromParam = "from"
+	ToParam   = "to"
 )
 
 // method upgradeRisksPrediction return a recommendation to upgrade or not a cluster
@@ -81,37 +78,126 @@ func (server *HTTPServer) upgradeRisksPrediction(writer http.ResponseWriter, req
 		return
 	}
 
-	switch clusterName {
-	case ClusterManaged:
+	prediction, err := server.Storage.GetPredictionForCluster(clusterName)
+	if err != nil {
+		sendPredictionError(writer, err)
+		return
+	}
+
+	writer.Header().Set(contentType, appJSON)
+	resp := responses.BuildOkResponseWithData("upgrade_recommendation", prediction)
+	resp["meta"] = map[string]string{
+		"last_checked_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	err = responses.SendOK(writer, resp)
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// sendPredictionError sends response for cluster without upgrade risks
+// prediction
+func sendPredictionError(writer http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, storage.ErrClusterManaged):
 		log.Info().Msg("managed cluster case")
 		err = responses.SendNoContent(writer)
-		if err != nil {
-			log.Error().Err(err).Msg(responseDataError)
-		}
 
-	case ClusterNoAMS:
+	case errors.Is(err, storage.ErrAMSUnavailable):
 		log.Info().Msg("No AMS available case")
 		err = responses.SendServiceUnavailable(writer, "AMS service unavailable")
-		if err != nil {
-			log.Error().Err(err).Msg(responseDataError)
-		}
 
-	case ClusterUnavailable:
+	case errors.Is(err, storage.ErrPredictionUnavailable):
 		log.Info().Msg("No Upgrade Risks Prediction service available case")
 		err = responses.SendServiceUnavailable(writer, "Upgrade Risks Prediction service unavailable")
-		if err != nil {
-			log.Error().Err(err).Msg(responseDataError)
-		}
 
-	case ClusterNoData:
+	default:
 		log.Info().Msg(clusterHasNoData)
 		err = responses.SendNotFound(writer, clusterHasNoData)
-		if err != nil {
-			log.Error().Err(err).Msg(responseDataError)
-		}
+	}
 
-	default:
-		server.sendOkResponse(clusterName, writer)
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// readTimeParam reads optional query parameter with timestamp in RFC3339
+// format
+func readTimeParam(query url.Values, name string, defaultValue time.Time) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return defaultValue, fmt.Errorf("parameter '%s' must be a timestamp in RFC3339 format", name)
+	}
+	return timestamp, nil
+}
+
+// method upgradeRisksPredictionHistory returns past upgrade risks
+// predictions for the cluster made in time range selected by optional from
+// and to query parameters. Predictions are sorted by time when they were made.
+// Responses for clusters without predictions are the same as for
+// upgradeRisksPrediction.
+//
+// Response format should look like:
+//
+//	{
+//		"status": "ok",
+//		"meta": {
+//			"from": "2024-03-01T00:00:00Z",
+//			"to": "2024-03-31T00:00:00Z"
+//		},
+//		"upgrade_recommendations": [
+//			{
+//				"last_checked_at": "2024-03-02T10:00:00Z",
+//				"upgrade_recommended": true,
+//				"upgrade_risks_predictors": {
+//					"alerts": [],
+//					"operator_conditions": []
+//				}
+//			}
+//		]
+//	}
+func (server *HTTPServer) upgradeRisksPredictionHistory(writer http.ResponseWriter, request *http.Request) {
+	clusterName, err := readClusterName(writer, request)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	query := request.URL.Query()
+	to, err := readTimeParam(query, ToParam, time.Now().UTC())
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+	from, err := readTimeParam(query, FromParam, time.Time{})
+	if err != nil {
+		sendImproperParameter(writer, err)
+		return
+	}
+	if from.After(to) {
+		sendImproperParameter(writer, fmt.Errorf("parameter '%s' must not be after '%s'", FromParam, ToParam))
+		return
+	}
+
+	history, err := server.Storage.GetPredictionHistoryForCluster(clusterName, from, to)
+	if err != nil {
+		sendPredictionError(writer, err)
+		return
+	}
+
+	resp := responses.BuildOkResponseWithData("upgrade_recommendations", history)
+	resp["meta"] = map[string]string{
+		FromParam: from.Format(time.RFC3339),
+		ToParam:   to.Format(time.RFC3339),
+	}
+	err = responses.SendOK(writer, resp)
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
 	}
 }
 
@@ -128,57 +214,7 @@ func (server *HTTPServer) upgradeRisksPredictionMultiCluster(writer http.Respons
 	for _, cluster := range clusterList {
 		clusterName := types.ClusterName(cluster)
 
-		switch clusterName {
-		case ClusterManaged:
-			log.Info().Msg("managed cluster case")
-			responseArray = append(responseArray, types.ClusterUpgradeRiskPrediction{
-				Cluster: cluster,
-				Status:  "ok",
-			})
-
-		case ClusterNoAMS:
-			log.Info().Msg("No AMS available case")
-			responseArray = append(responseArray, types.ClusterUpgradeRiskPrediction{
-				Cluster: cluster,
-				Status:  "AMS service not available",
-			})
-
-		case ClusterUnavailable:
-			log.Info().Msg("No Upgrade Risks Prediction service available case")
-			responseArray = append(responseArray, types.ClusterUpgradeRiskPrediction{
-				Cluster: cluster,
-				Status:  "Upgrade Risks Prediction service unavailable",
-			})
-
-		case ClusterOkFailUpgrade:
-			log.Info().Msg("Cluster is not recommended to upgrade")
-			predictors := buildNotEmptyPredictors()
-			responseArray = append(responseArray, types.ClusterUpgradeRiskPrediction{
-				Cluster:     cluster,
-				Status:      "ok",
-				Recommended: false,
-				Predictors:  &predictors,
-			})
-
-		case ClusterOk:
-			responseArray = append(responseArray, types.ClusterUpgradeRiskPrediction{
-				Cluster:     cluster,
-				Status:      "ok",
-				Recommended: true,
-				Predictors: &types.UpgradeRisksPredictors{
-					Alerts:             []types.Alert{},
-					OperatorConditions: []types.OperatorCondition{},
-				},
-			})
-
-		case ClusterNoData:
-		default:
-			log.Info().Msg(clusterHasNoData)
-			responseArray = append(responseArray, types.ClusterUpgradeRiskPrediction{
-				Cluster: cluster,
-				Status:  clusterHasNoData,
-			})
-		}
+		responseArray = append(responseArray, server.clusterPrediction(clusterName))
 	}
 
 	err := responses.SendOK(
@@ -195,86 +231,37 @@ func (server *HTTPServer) upgradeRisksPredictionMultiCluster(writer http.Respons
 	}
 }
 
-func (server *HTTPServer) sendOkResponse(clusterName types.ClusterName, writer http.ResponseWriter) {
-	prediction, err := server.Storage.GetPredictionForCluster(clusterName)
-	if err != nil {
-		log.Error().Err(err).Msg("error retrieving upgrade prediction from storage")
-		handleServerError(err)
-		err = responses.SendNotFound(writer, err.Error())
-		if err != nil {
-			log.Error().Err(err).Msg(responseDataError)
-		}
-		return
+// clusterPrediction returns upgrade risks prediction for one cluster in the
+// format used by multi cluster endpoint. It is based on the same data as the
+// single cluster endpoint.
+func (server *HTTPServer) clusterPrediction(clusterName types.ClusterName) types.ClusterUpgradeRiskPrediction {
+	result := types.ClusterUpgradeRiskPrediction{
+		Cluster: string(clusterName),
 	}
 
-	if clusterName == ClusterOkFailUpgrade {
-		buildNotRecommendedPrediction(prediction)
-	}
+	prediction, err := server.Storage.GetPredictionForCluster(clusterName)
+	switch {
+	case errors.Is(err, storage.ErrClusterManaged):
+		log.Info().Msg("managed cluster case")
+		result.Status = statusOk
 
-	writer.Header().Set(contentType, appJSON)
-	resp := responses.BuildOkResponseWithData("upgrade_recommendation", prediction)
-	resp["meta"] = map[string]string{
-		"last_checked_at": time.Now().UTC().Format(time.RFC3339),
-	}
-	err = responses.SendOK(writer, resp)
-	if err != nil {
-		log.Error().Err(err).Msg(responseDataError)
-	}
-}
+	case errors.Is(err, storage.ErrAMSUnavailable):
+		log.Info().Msg("No AMS available case")
+		result.Status = "AMS service not available"
 
-func buildNotRecommendedPrediction(prediction *types.UpgradeRiskPrediction) {
-	prediction.Recommended = false
-	prediction.Predictors = buildNotEmptyPredictors()
-}
+	case errors.Is(err, storage.ErrPredictionUnavailable):
+		log.Info().Msg("No Upgrade Risks Prediction service available case")
+		result.Status = "Upgrade Risks Prediction service unavailable"
 
-func buildNotEmptyPredictors() types.UpgradeRisksPredictors {
-	return types.UpgradeRisksPredictors{
-		Alerts: []types.Alert{
-			{
-				Name:      "alert1",
-				Namespace: "namespace1",
-				Severity:  "info",
-				URL:       "https://my-cluster.com/monitoring/alerts?orderBy=asc&sortBy=Severity&alert-name=alert1",
-			},
-			{
-				Name:      "alert2",
-				Namespace: "namespace2",
-				Severity:  "warning",
-				URL:       "https://my-cluster.com/monitoring/alerts?orderBy=asc&sortBy=Severity&alert-name=alert2",
-			},
-			{
-				Name:      "alert3",
-				Namespace: "namespace3",
-				Severity:  "critical",
-				URL:       "https://my-cluster.com/monitoring/alerts?orderBy=asc&sortBy=Severity&alert-name=alert3",
-			},
-		},
+	case err != nil:
+		log.Info().Msg(clusterHasNoData)
+		result.Status = clusterHasNoData
 
-		OperatorConditions: []types.OperatorCondition{
-			{
-				Name:      "foc1",
-				Condition: "Degraded",
-				Reason:    reasonNotExpected,
-				URL:       "https://my-cluster.com/k8s/cluster/config.openshift.io~v1~ClusterOperator/foc1",
-			},
-			{
-				Name:      "foc2",
-				Condition: "Failing",
-				Reason:    reasonNotExpected,
-				URL:       "https://my-cluster.com/k8s/cluster/config.openshift.io~v1~ClusterOperator/foc2",
-			},
-			{
-				Name:      "foc3",
-				Condition: "Not Available",
-				Reason:    reasonNotExpected,
-				URL:       "https://my-cluster.com/k8s/cluster/config.openshift.io~v1~ClusterOperator/foc3",
-			},
-			{
-				Name:      "foc4",
-				Condition: "Not Upgradeable",
-				Reason:    reasonNotExpected,
-				URL:       "https://my-cluster.com/k8s/cluster/config.openshift.io~v1~ClusterOperator/foc4",
-			},
-		},
+	default:
+		result.Status = statusOk
+		result.Recommended = prediction.Recommended
+		result.Predictors = &prediction.Predictors
 	}
+
+	return result
 }
@@ -0,0 +1,122 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/server"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// TestUpgradeRisksPredictionEndpointsAgree checks that single cluster and
+// multi cluster endpoints answer from the same fixture
+func TestUpgradeRisksPredictionEndpointsAgree(t *testing.T) {
+	config := server.Configuration{APIPrefix: "/api/", AuthType: server.AuthTypeNone}
+	handler, _ := newTestHandler(t, config, nil)
+
+	expected := []struct {
+		cluster     string
+		singleCode  int
+		multiStatus string
+	}{
+		{"00000001-624a-49a5-bab8-4fdc5e51a266", http.StatusOK, "ok"},
+		{"00000003-eeee-eeee-eeee-000000000001", http.StatusOK, "ok"},
+		{"6cab9726-c2be-438e-af11-db846a678abb", http.StatusNoContent, "ok"},
+		{"c60ba611-6af4-4d62-9b9e-36344da5e7bc", http.StatusServiceUnavailable, "AMS service not available"},
+		{"897ec1a1-4679-4122-aacb-f0ae9f9e1a5f", http.StatusServiceUnavailable, "Upgrade Risks Prediction service unavailable"},
+		{"234ec1a1-4679-4122-aacb-f0ae9f9e1a56", http.StatusNotFound, "No data for the cluster"},
+		{"00000000-0000-0000-0000-000000000000", http.StatusNotFound, "No data for the cluster"},
+	}
+
+	clusters := make([]string, 0, len(expected))
+	for _, e := range expected {
+		request := httptest.NewRequest(http.MethodGet,
+			"/api/cluster/"+e.cluster+"/upgrade-risks-prediction", http.NoBody)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, e.singleCode, recorder.Code, e.cluster)
+		clusters = append(clusters, `"`+e.cluster+`"`)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/api/upgrade-risks-prediction",
+		strings.NewReader(`{"clusters": [`+strings.Join(clusters, ",")+`]}`))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Predictions []types.ClusterUpgradeRiskPrediction `json:"predictions"`
+	}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Len(t, response.Predictions, len(expected))
+	for i, prediction := range response.Predictions {
+		assert.Equal(t, expected[i].cluster, prediction.Cluster)
+		assert.Equal(t, expected[i].multiStatus, prediction.Status, prediction.Cluster)
+	}
+	assert.True(t, response.Predictions[0].Recommended)
+	assert.False(t, response.Predictions[1].Recommended)
+	assert.Len(t, response.Predictions[1].Predictors.Alerts, 3)
+}
+
+// TestUpgradeRisksPredictionHistory checks that past predictions are
+// returned for selected time range
+func TestUpgradeRisksPredictionHistory(t *testing.T) {
+	config := server.Configuration{APIPrefix: "/api/", AuthType: server.AuthTypeNone}
+	handler, _ := newTestHandler(t, config, nil)
+
+	readHistory := func(cluster, query string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet,
+			"/api/cluster/"+cluster+"/upgrade-risks-prediction/history"+query, http.NoBody)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := readHistory("00000003-eeee-eeee-eeee-000000000001",
+		"?from=2024-03-02T00:00:00Z&to=2024-03-31T00:00:00Z")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Status          string                                   `json:"status"`
+		Meta            map[string]string                        `json:"meta"`
+		Recommendations []types.UpgradeRiskPredictionHistoryItem `json:"upgrade_recommendations"`
+	}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, "ok", response.Status)
+	assert.Equal(t, "2024-03-02T00:00:00Z", response.Meta["from"])
+	assert.Len(t, response.Recommendations, 2)
+	assert.Equal(t, "2024-03-02T10:00:00Z", response.Recommendations[0].LastCheckedAt)
+	assert.False(t, response.Recommendations[0].Recommended)
+
+	// responses for clusters without prediction are the same as for the
+	// current prediction
+	assert.Equal(t, http.StatusNoContent, readHistory("6cab9726-c2be-438e-af11-db846a678abb", "").Code)
+	assert.Equal(t, http.StatusNotFound, readHistory("00000000-0000-0000-0000-000000000000", "").Code)
+
+	// improper time range
+	assert.Equal(t, http.StatusBadRequest,
+		readHistory("00000003-eeee-eeee-eeee-000000000001", "?from=yesterday").Code)
+	assert.Equal(t, http.StatusBadRequest,
+		readHistory("00000003-eeee-eeee-eeee-000000000001", "?from=2024-03-02T00:00:00Z&to=2024-03-01T00:00:00Z").Code)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// Handlers for endpoints to like, dislike, and reset vote on rule hitting
+// given cluster. Votes are reflected in user_vote attribute of rule hits in
+// served reports.
+
+import (
+	"net/http"
+
+	"github.com/RedHatInsights/insights-operator-utils/responses"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/metrics"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// voteOnRule stores user vote on rule for given cluster
+func (server *HTTPServer) voteOnRule(writer http.ResponseWriter, request *http.Request, vote types.UserVote) {
+	clusterName, ruleID, ok := server.readClusterAndRule(writer, request)
+	if !ok {
+		return
+	}
+
+	userID := readUserID(request)
+	server.Storage.VoteOnRule(clusterName, ruleID, userID, vote)
+	metrics.FeedbackOnRules.Inc()
+
+	log.Info().
+		Str("cluster", string(clusterName)).
+		Str("rule", string(ruleID)).
+		Str("user", string(userID)).
+		Int("vote", int(vote)).
+		Msg("Vote on rule")
+
+	err := responses.SendOK(writer, responses.BuildOkResponse())
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
+
+// likeRule likes rule for given cluster
+func (server *HTTPServer) likeRule(writer http.ResponseWriter, request *http.Request) {
+	server.voteOnRule(writer, request, types.UserVoteLike)
+}
+
+// dislikeRule dislikes rule for given cluster
+func (server *HTTPServer) dislikeRule(writer http.ResponseWriter, request *http.Request) {
+	server.voteOnRule(writer, request, types.UserVoteDislike)
+}
+
+// resetVoteOnRule resets vote on rule for given cluster
+func (server *HTTPServer) resetVoteOnRule(writer http.ResponseWriter, request *http.Request) {
+	server.voteOnRule(writer, request, types.UserVoteNone)
+}
+
+// getVoteOnRule returns current user vote on rule for given cluster
+func (server *HTTPServer) getVoteOnRule(writer http.ResponseWriter, request *http.Request) {
+	clusterName, ruleID, ok := server.readClusterAndRule(writer, request)
+	if !ok {
+		return
+	}
+
+	vote := server.Storage.GetUserVote(clusterName, ruleID, readUserID(request))
+
+	err := responses.SendOK(writer, responses.BuildOkResponseWithData("vote", vote))
+	if err != nil {
+		log.Error().Err(err).Msg(responseDataError)
+	}
+}
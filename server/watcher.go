@@ -0,0 +1,136 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+// File watcher that reloads all data when any of watched files is changed.
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// editors and tools usually write files in several steps, so it is better to
+// wait a bit before data are reloaded
+const reloadDelay = 500 * time.Millisecond
+
+// WatchFiles method starts watching given files and directories. All data
+// are reloaded when any of them (or any file in watched directory) is
+// changed. Watching runs in separate goroutine.
+func (server *HTTPServer) WatchFiles(paths ...string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	directories := make(map[string]struct{})
+	files := make(map[string]struct{})
+
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			_ = watcher.Close()
+			return err
+		}
+
+		fileInfo, err := os.Stat(absPath)
+		if err != nil {
+			_ = watcher.Close()
+			return err
+		}
+
+		// files are usually replaced by editors, so it is needed to
+		// watch the whole directory instead of the file itself
+		directory := absPath
+		if fileInfo.IsDir() {
+			directories[absPath] = struct{}{}
+		} else {
+			files[absPath] = struct{}{}
+			directory = filepath.Dir(absPath)
+		}
+
+		err = watcher.Add(directory)
+		if err != nil {
+			_ = watcher.Close()
+			return err
+		}
+		log.Info().Str("path", absPath).Msg("Watching for changes")
+	}
+
+	go server.watch(watcher, directories, files)
+
+	return nil
+}
+
+// watch method processes events from file watcher and reloads data when some
+// watched file is changed
+func (server *HTTPServer) watch(watcher *fsnotify.Watcher, directories, files map[string]struct{}) {
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	var timer *time.Timer
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !isWatchedChange(event, directories, files) {
+				continue
+			}
+			log.Info().Str("file", event.Name).Str("operation", event.Op.String()).Msg("File changed")
+
+			// postpone reload until all changes are made
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(reloadDelay, func() {
+				err := server.Reload()
+				if err != nil {
+					log.Error().Err(err).Msg("Data reload failed, previous data are kept")
+				}
+			})
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error().Err(err).Msg("File watcher error")
+		}
+	}
+}
+
+// isWatchedChange function checks if the event is related to watched file or
+// directory and if it changes the file content
+func isWatchedChange(event fsnotify.Event, directories, files map[string]struct{}) bool {
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) &&
+		!event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+		return false
+	}
+
+	if _, found := files[event.Name]; found {
+		return true
+	}
+
+	_, found := directories[filepath.Dir(event.Name)]
+	return found
+}
@@ -0,0 +1,191 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// Acknowledgements of rules made in given organization. Initial acks are read
+// from fixture file stored in mock data directory when storage is
+// constructed. Acks are changed by users, so they are kept when mock data are
+// reloaded.
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// AcksFileName is name of fixture file with initial acks that is expected to
+// be stored in mock data directory. The file is optional.
+const AcksFileName = "acks.yaml"
+
+// ErrAckNotFound is returned when rule has not been acked in organization
+var ErrAckNotFound = errors.New("rule not found -> justification can not be changed")
+
+// AckStorage represents storage of rule acknowledgements. All acks are
+// scoped by organization.
+type AckStorage interface {
+	ListOfAcks(orgID types.OrgID) []types.Acknowledge
+	GetAck(orgID types.OrgID, ruleSelector types.RuleSelector) (types.Acknowledge, bool)
+	AddAck(orgID types.OrgID, ruleSelector types.RuleSelector, justification, createdBy string) (types.Acknowledge, bool)
+	UpdateAck(orgID types.OrgID, ruleSelector types.RuleSelector, justification *string) (types.Acknowledge, error)
+	DeleteAck(orgID types.OrgID, ruleSelector types.RuleSelector) error
+}
+
+// AckEntry represents one ack stored in fixture file
+type AckEntry struct {
+	OrgID         types.OrgID        `yaml:"org_id"`
+	Rule          types.RuleSelector `yaml:"rule"`
+	Justification string             `yaml:"justification"`
+	CreatedBy     string             `yaml:"created_by"`
+	CreatedAt     string             `yaml:"created_at"`
+	UpdatedAt     string             `yaml:"updated_at"`
+}
+
+// ackKey identifies one ack
+type ackKey struct {
+	orgID        types.OrgID
+	ruleSelector types.RuleSelector
+}
+
+// formattedNow function returns current time formatted according to RFC3339
+func formattedNow() string {
+	return time.Now().Format(time.RFC3339)
+}
+
+// readAcks function reads fixture file with initial acks stored in given
+// directory. No acks are returned when the file does not exist.
+func readAcks(path string) (map[ackKey]types.Acknowledge, error) {
+	acks := make(map[ackKey]types.Acknowledge)
+
+	var entries []AckEntry
+	err := readFixtureFile(path, AcksFileName, &entries)
+	if err != nil {
+		return acks, err
+	}
+
+	for _, entry := range entries {
+		key := ackKey{entry.OrgID, entry.Rule}
+		if _, found := acks[key]; found {
+			return acks, fmt.Errorf("improper acks file: rule %s acked twice in organization %d",
+				entry.Rule, entry.OrgID)
+		}
+		acks[key] = types.Acknowledge{
+			Acknowledged:  true,
+			Rule:          string(entry.Rule),
+			Justification: entry.Justification,
+			CreatedBy:     entry.CreatedBy,
+			CreatedAt:     entry.CreatedAt,
+			UpdatedAt:     entry.UpdatedAt,
+		}
+	}
+
+	return acks, nil
+}
+
+// ListOfAcks returns all acks made in given organization sorted by rule
+// selector
+func (storage *MemoryStorage) ListOfAcks(orgID types.OrgID) []types.Acknowledge {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	acks := make([]types.Acknowledge, 0)
+	for key, ack := range storage.acks {
+		if key.orgID == orgID {
+			acks = append(acks, ack)
+		}
+	}
+
+	sort.Slice(acks, func(i, j int) bool {
+		return acks[i].Rule < acks[j].Rule
+	})
+	return acks
+}
+
+// GetAck returns ack of given rule made in given organization
+func (storage *MemoryStorage) GetAck(orgID types.OrgID, ruleSelector types.RuleSelector) (types.Acknowledge, bool) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	ack, found := storage.acks[ackKey{orgID, ruleSelector}]
+	return ack, found
+}
+
+// AddAck acks given rule in given organization. Existing ack is returned
+// untouched when the rule has been acked already, the second return value is
+// true when new ack has been created.
+func (storage *MemoryStorage) AddAck(
+	orgID types.OrgID, ruleSelector types.RuleSelector, justification, createdBy string,
+) (types.Acknowledge, bool) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	key := ackKey{orgID, ruleSelector}
+	if ack, found := storage.acks[key]; found {
+		return ack, false
+	}
+
+	if storage.acks == nil {
+		storage.acks = make(map[ackKey]types.Acknowledge)
+	}
+	ack := types.Acknowledge{
+		Acknowledged:  true,
+		Rule:          string(ruleSelector),
+		Justification: justification,
+		CreatedBy:     createdBy,
+		CreatedAt:     formattedNow(),
+		UpdatedAt:     formattedNow(),
+	}
+	storage.acks[key] = ack
+	return ack, true
+}
+
+// UpdateAck updates UpdatedAt attribute of existing ack. Justification is
+// changed too when it is provided.
+func (storage *MemoryStorage) UpdateAck(
+	orgID types.OrgID, ruleSelector types.RuleSelector, justification *string,
+) (types.Acknowledge, error) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	key := ackKey{orgID, ruleSelector}
+	ack, found := storage.acks[key]
+	if !found {
+		return ack, ErrAckNotFound
+	}
+
+	ack.UpdatedAt = formattedNow()
+	if justification != nil {
+		ack.Justification = *justification
+	}
+	storage.acks[key] = ack
+	return ack, nil
+}
+
+// DeleteAck deletes ack of given rule made in given organization
+func (storage *MemoryStorage) DeleteAck(orgID types.OrgID, ruleSelector types.RuleSelector) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	key := ackKey{orgID, ruleSelector}
+	if _, found := storage.acks[key]; !found {
+		return ErrAckNotFound
+	}
+	delete(storage.acks, key)
+	return nil
+}
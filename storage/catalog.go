@@ -0,0 +1,135 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// Catalog of organizations and clusters that is read from the manifest file
+// stored in mock data directory. Thanks to this catalog it is possible to add
+// new clusters (or whole organizations) without the need to change the code.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// CatalogFileName is name of manifest file with organizations and clusters
+// that is expected to be stored in mock data directory.
+const CatalogFileName = "catalog.yaml"
+
+// Catalog represents the whole manifest file with organizations, clusters and
+// clusters that change their report periodically.
+type Catalog struct {
+	Organizations    []OrganizationEntry                       `yaml:"organizations"`
+	ChangingClusters map[types.ClusterName][]types.ClusterName `yaml:"changing_clusters"`
+}
+
+// OrganizationEntry represents one organization stored in catalog. When
+// Forbidden attribute is set, all operations with such organization ends with
+// "no permissions" error.
+type OrganizationEntry struct {
+	OrgID     types.OrgID    `yaml:"org_id"`
+	Forbidden bool           `yaml:"forbidden"`
+	Clusters  []ClusterEntry `yaml:"clusters"`
+}
+
+// ClusterEntry represents one cluster stored in catalog. Report attribute
+// contains name of file (relative to mock data directory) with cluster
+// report. If not set, the file name report_{cluster}.json is used instead.
+type ClusterEntry struct {
+	Name        types.ClusterName `yaml:"cluster"`
+	DisplayName string            `yaml:"display_name"`
+	Report      string            `yaml:"report"`
+}
+
+// ReportFile returns name of file with report for given cluster
+func (entry ClusterEntry) ReportFile() string {
+	if entry.Report != "" {
+		return entry.Report
+	}
+	return reportFileName(entry.Name)
+}
+
+// reportFileName constructs default name of file with report for given
+// cluster
+func reportFileName(clusterName types.ClusterName) string {
+	return "report_" + string(clusterName) + ".json"
+}
+
+// ReadCatalog function reads and parses catalog file stored in given
+// directory.
+func ReadCatalog(path string) (Catalog, error) {
+	var catalog Catalog
+
+	fileName := filepath.Join(path, CatalogFileName)
+
+	// disable "G304 (CWE-22): Potential file inclusion via variable"
+	content, err := os.ReadFile(fileName) // #nosec G304
+	if err != nil {
+		return catalog, err
+	}
+
+	err = yaml.Unmarshal(content, &catalog)
+	if err != nil {
+		return catalog, fmt.Errorf("unable to parse catalog file %s: %w", fileName, err)
+	}
+
+	err = catalog.validate()
+	if err != nil {
+		return catalog, fmt.Errorf("improper catalog file %s: %w", fileName, err)
+	}
+
+	return catalog, nil
+}
+
+// validate method checks that each organization and each cluster is declared
+// just once in catalog.
+func (catalog Catalog) validate() error {
+	orgs := make(map[types.OrgID]struct{})
+	clusters := make(map[types.ClusterName]types.OrgID)
+
+	for _, org := range catalog.Organizations {
+		if org.OrgID == 0 {
+			return fmt.Errorf("organization ID is not set or is zero")
+		}
+		if _, found := orgs[org.OrgID]; found {
+			return fmt.Errorf("organization %d is declared more than once", org.OrgID)
+		}
+		orgs[org.OrgID] = struct{}{}
+
+		for _, cluster := range org.Clusters {
+			if cluster.Name == "" {
+				return fmt.Errorf("cluster name is not set for organization %d", org.OrgID)
+			}
+			if owner, found := clusters[cluster.Name]; found {
+				return fmt.Errorf("cluster %s is already declared for organization %d", cluster.Name, owner)
+			}
+			clusters[cluster.Name] = org.OrgID
+		}
+	}
+
+	for changingCluster, variants := range catalog.ChangingClusters {
+		if len(variants) == 0 {
+			return fmt.Errorf("no report variants for changing cluster %s", changingCluster)
+		}
+	}
+
+	return nil
+}
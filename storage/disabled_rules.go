@@ -0,0 +1,88 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// Rules disabled for given cluster. Disabled rules are not read from mock
+// data directory, so they are kept when mock data are reloaded.
+
+import (
+	"sort"
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// DisabledRule represents rule disabled for one cluster
+type DisabledRule struct {
+	ClusterName   types.ClusterName
+	RuleID        types.RuleID
+	Justification string
+	DisabledAt    time.Time
+}
+
+// disabledRuleKey identifies one disabled rule
+type disabledRuleKey struct {
+	clusterName types.ClusterName
+	ruleID      types.RuleID
+}
+
+// DisableRuleForCluster disables rule for given cluster. Justification and
+// timestamp are updated when the rule has been disabled already.
+func (storage *MemoryStorage) DisableRuleForCluster(
+	clusterName types.ClusterName, ruleID types.RuleID, justification string,
+) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	if storage.disabledRules == nil {
+		storage.disabledRules = make(map[disabledRuleKey]DisabledRule)
+	}
+	storage.disabledRules[disabledRuleKey{clusterName, ruleID}] = DisabledRule{
+		ClusterName:   clusterName,
+		RuleID:        ruleID,
+		Justification: justification,
+		DisabledAt:    time.Now().UTC(),
+	}
+}
+
+// EnableRuleForCluster re-enables rule for given cluster. Nothing happens
+// when the rule is not disabled.
+func (storage *MemoryStorage) EnableRuleForCluster(clusterName types.ClusterName, ruleID types.RuleID) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	delete(storage.disabledRules, disabledRuleKey{clusterName, ruleID})
+}
+
+// ListOfDisabledRulesForCluster returns all rules disabled for given cluster
+// sorted by rule ID
+func (storage *MemoryStorage) ListOfDisabledRulesForCluster(clusterName types.ClusterName) []DisabledRule {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	disabledRules := make([]DisabledRule, 0)
+	for key, disabledRule := range storage.disabledRules {
+		if key.clusterName == clusterName {
+			disabledRules = append(disabledRules, disabledRule)
+		}
+	}
+
+	sort.Slice(disabledRules, func(i, j int) bool {
+		return disabledRules[i].RuleID < disabledRules[j].RuleID
+	})
+	return disabledRules
+}
@@ -0,0 +1,295 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// DVO (Deployment Validation Operator) checks and workloads reported for
+// clusters. Both are read from fixture files stored in mock data directory,
+// so new namespace scenarios can be added without the need to change the
+// code. DVO data are part of mock data, so they are reloaded together with
+// reports.
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// DVOChecksFileName is name of fixture file with DVO checks that is expected
+// to be stored in mock data directory. The file is optional.
+const DVOChecksFileName = "dvo_checks.yaml"
+
+// DVOWorkloadsFileName is name of fixture file with DVO workloads that is
+// expected to be stored in mock data directory. The file is optional.
+const DVOWorkloadsFileName = "dvo_workloads.yaml"
+
+// DVOStorage represents storage of DVO checks and workloads
+type DVOStorage interface {
+	ListOfDVOClusters() []types.ClusterName
+	GetDVOCluster(clusterName types.ClusterName) (DVOCluster, bool)
+	GetDVOWorkloads(clusterName types.ClusterName) ([]types.DVOWorkload, bool)
+	GetDVOCheck(check string) (DVOCheck, bool)
+}
+
+// DVOCluster contains organization and display name of cluster with DVO
+// workloads and metadata of all its namespaces
+type DVOCluster struct {
+	OrgID       types.OrgID
+	DisplayName string
+	Namespaces  map[string]DVONamespace
+}
+
+// DVONamespace contains name of namespace and timestamps of the last report
+type DVONamespace struct {
+	Name          string
+	ReportedAt    string
+	LastCheckedAt string
+}
+
+// DVOCheck represents one check stored in fixture file. Modified timestamp,
+// more info and template data are optional.
+type DVOCheck struct {
+	Check        string      `yaml:"check"`
+	Description  string      `yaml:"description"`
+	Remediation  string      `yaml:"remediation"`
+	Modified     string      `yaml:"modified"`
+	MoreInfo     string      `yaml:"more_info"`
+	TemplateData interface{} `yaml:"template_data"`
+}
+
+// DVOClusterEntry represents workloads for one cluster stored in fixture
+// file. Timestamps are used for all namespaces that do not override them.
+type DVOClusterEntry struct {
+	Cluster       types.ClusterName   `yaml:"cluster"`
+	OrgID         types.OrgID         `yaml:"org_id"`
+	DisplayName   string              `yaml:"display_name"`
+	ReportedAt    string              `yaml:"reported_at"`
+	LastCheckedAt string              `yaml:"last_checked_at"`
+	Namespaces    []DVONamespaceEntry `yaml:"namespaces"`
+}
+
+// DVONamespaceEntry represents workloads for one namespace stored in fixture
+// file. Timestamps are optional.
+type DVONamespaceEntry struct {
+	UUID          string             `yaml:"uuid"`
+	Name          string             `yaml:"name"`
+	ReportedAt    string             `yaml:"reported_at"`
+	LastCheckedAt string             `yaml:"last_checked_at"`
+	Workloads     []DVOWorkloadEntry `yaml:"workloads"`
+}
+
+// DVOWorkloadEntry represents one workload stored in fixture file
+type DVOWorkloadEntry struct {
+	Check string `yaml:"check"`
+	Kind  string `yaml:"kind"`
+	UID   string `yaml:"uid"`
+}
+
+// readDVOChecks function reads and validates DVO checks
+func readDVOChecks(path string) (map[string]DVOCheck, error) {
+	var entries []DVOCheck
+	err := readFixtureFile(path, DVOChecksFileName, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make(map[string]DVOCheck, len(entries))
+	for _, check := range entries {
+		if check.Check == "" {
+			return nil, fmt.Errorf("improper DVO checks file: check name is not set")
+		}
+		if _, found := checks[check.Check]; found {
+			return nil, fmt.Errorf("improper DVO checks file: check %s defined twice", check.Check)
+		}
+		if check.Modified != "" {
+			if err := checkTimestamp(check.Modified); err != nil {
+				return nil, fmt.Errorf("improper DVO checks file: check %s: %w", check.Check, err)
+			}
+		}
+		checks[check.Check] = check
+	}
+	return checks, nil
+}
+
+// checkTimestamp checks if timestamp stored in fixture file has the expected
+// format
+func checkTimestamp(timestamp string) error {
+	_, err := time.Parse(time.RFC3339, timestamp)
+	return err
+}
+
+// checkDVOCluster checks metadata of cluster stored in fixture file
+func checkDVOCluster(cluster DVOClusterEntry) error {
+	if cluster.OrgID == 0 {
+		return errors.New("organization ID is not set")
+	}
+	if err := checkTimestamp(cluster.ReportedAt); err != nil {
+		return err
+	}
+	return checkTimestamp(cluster.LastCheckedAt)
+}
+
+// namespaceMetadata returns metadata of namespace stored in fixture file.
+// Timestamps are inherited from cluster when they are not set.
+func namespaceMetadata(cluster DVOClusterEntry, namespace DVONamespaceEntry) (DVONamespace, error) {
+	metadata := DVONamespace{
+		Name:          namespace.Name,
+		ReportedAt:    cluster.ReportedAt,
+		LastCheckedAt: cluster.LastCheckedAt,
+	}
+	if namespace.UUID == "" {
+		return metadata, errors.New("namespace UUID is not set")
+	}
+	if namespace.ReportedAt != "" {
+		if err := checkTimestamp(namespace.ReportedAt); err != nil {
+			return metadata, err
+		}
+		metadata.ReportedAt = namespace.ReportedAt
+	}
+	if namespace.LastCheckedAt != "" {
+		if err := checkTimestamp(namespace.LastCheckedAt); err != nil {
+			return metadata, err
+		}
+		metadata.LastCheckedAt = namespace.LastCheckedAt
+	}
+	return metadata, nil
+}
+
+// readDVOWorkloads function reads and validates DVO clusters and their
+// workloads. All workloads need to refer to known check.
+func readDVOWorkloads(path string, checks map[string]DVOCheck) (
+	map[types.ClusterName]DVOCluster, map[types.ClusterName][]types.DVOWorkload, error,
+) {
+	var entries []DVOClusterEntry
+	err := readFixtureFile(path, DVOWorkloadsFileName, &entries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clusters := make(map[types.ClusterName]DVOCluster, len(entries))
+	workloads := make(map[types.ClusterName][]types.DVOWorkload, len(entries))
+	for _, cluster := range entries {
+		if _, found := workloads[cluster.Cluster]; found {
+			return nil, nil, fmt.Errorf("improper DVO workloads file: cluster %s defined twice", cluster.Cluster)
+		}
+		if err := checkDVOCluster(cluster); err != nil {
+			return nil, nil, fmt.Errorf("improper DVO workloads file: cluster %s: %w", cluster.Cluster, err)
+		}
+
+		dvoCluster := DVOCluster{
+			OrgID:       cluster.OrgID,
+			DisplayName: cluster.DisplayName,
+			Namespaces:  make(map[string]DVONamespace, len(cluster.Namespaces)),
+		}
+		workloadsForCluster := make([]types.DVOWorkload, 0)
+		for _, namespace := range cluster.Namespaces {
+			metadata, err := namespaceMetadata(cluster, namespace)
+			if err != nil {
+				return nil, nil, fmt.Errorf("improper DVO workloads file: cluster %s: %w", cluster.Cluster, err)
+			}
+			dvoCluster.Namespaces[namespace.UUID] = metadata
+
+			for _, workload := range namespace.Workloads {
+				check, found := checks[workload.Check]
+				if !found {
+					return nil, nil, fmt.Errorf("improper DVO workloads file: unknown check %s in namespace %s",
+						workload.Check, namespace.UUID)
+				}
+				if workload.UID == "" {
+					return nil, nil, fmt.Errorf("improper DVO workloads file: workload UID is not set in namespace %s",
+						namespace.UUID)
+				}
+				workloadsForCluster = append(workloadsForCluster, types.DVOWorkload{
+					Rule:             check.Check,
+					CheckDescription: check.Description,
+					CheckRemediation: check.Remediation,
+					Kind:             workload.Kind,
+					UID:              workload.UID,
+					NamespaceName:    namespace.Name,
+					NamespaceUID:     namespace.UUID,
+				})
+			}
+		}
+		clusters[cluster.Cluster] = dvoCluster
+		workloads[cluster.Cluster] = workloadsForCluster
+	}
+	return clusters, workloads, nil
+}
+
+// readDVOData function reads DVO checks and workloads stored in given
+// directory
+func readDVOData(path string, data *mockData) error {
+	checks, err := readDVOChecks(path)
+	if err != nil {
+		return err
+	}
+
+	clusters, workloads, err := readDVOWorkloads(path, checks)
+	if err != nil {
+		return err
+	}
+
+	data.dvoChecks = checks
+	data.dvoClusters = clusters
+	data.dvoWorkloads = workloads
+	return nil
+}
+
+// ListOfDVOClusters returns all clusters with DVO workloads sorted by name
+func (storage *MemoryStorage) ListOfDVOClusters() []types.ClusterName {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	clusters := make([]types.ClusterName, 0, len(storage.dvoWorkloads))
+	for cluster := range storage.dvoWorkloads {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i] < clusters[j]
+	})
+	return clusters
+}
+
+// GetDVOCluster returns organization, display name and namespaces of given
+// cluster with DVO workloads
+func (storage *MemoryStorage) GetDVOCluster(clusterName types.ClusterName) (DVOCluster, bool) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	cluster, found := storage.dvoClusters[clusterName]
+	return cluster, found
+}
+
+// GetDVOWorkloads returns all DVO workloads reported for given cluster
+func (storage *MemoryStorage) GetDVOWorkloads(clusterName types.ClusterName) ([]types.DVOWorkload, bool) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	workloads, found := storage.dvoWorkloads[clusterName]
+	return workloads, found
+}
+
+// GetDVOCheck returns DVO check with given name
+func (storage *MemoryStorage) GetDVOCheck(check string) (DVOCheck, bool) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	dvoCheck, found := storage.dvoChecks[check]
+	return dvoCheck, found
+}
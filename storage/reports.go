@@ -0,0 +1,177 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// Discovery and validation of files with cluster reports. All files named
+// report_{cluster}.json that are stored in mock data directory are read and
+// checked during storage initialization.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+const (
+	reportFilePrefix = "report_"
+	reportFileSuffix = ".json"
+)
+
+// reportFile represents structure of file with cluster report. It is used to
+// check if the file content has the expected format.
+type reportFile struct {
+	Report *struct {
+		Meta *struct {
+			Count         int             `json:"count"`
+			LastCheckedAt types.Timestamp `json:"last_checked_at"`
+		} `json:"meta"`
+		Data []map[string]interface{} `json:"data"`
+	} `json:"report"`
+	Status string `json:"status"`
+}
+
+// ValidateReport function checks if the report has the expected format:
+// object with "report" and "status" attributes, where report consists of
+// "meta" and "data" parts. Each rule hit stored in "data" part needs to have
+// rule ID and error key set.
+func ValidateReport(report []byte) error {
+	var parsed reportFile
+
+	decoder := json.NewDecoder(bytes.NewReader(report))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(&parsed)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Report == nil {
+		return errors.New("report attribute is missing")
+	}
+
+	if parsed.Report.Meta == nil {
+		return errors.New("meta attribute is missing in report")
+	}
+
+	if parsed.Report.Data == nil {
+		return errors.New("data attribute is missing in report")
+	}
+
+	for i, ruleHit := range parsed.Report.Data {
+		ruleID, ok := ruleHit["rule_id"].(string)
+		if !ok || ruleID == "" {
+			return fmt.Errorf("rule_id is not set for rule hit #%d", i)
+		}
+
+		details, ok := ruleHit["details"].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("details are not set for rule hit #%d", i)
+		}
+
+		errorKey, ok := details["error_key"].(string)
+		if !ok || errorKey == "" {
+			return fmt.Errorf("error_key is not set for rule hit #%d", i)
+		}
+	}
+
+	return nil
+}
+
+// readAndValidateReport function reads report from given file and checks its
+// format
+func readAndValidateReport(path, fileName string) (string, error) {
+	report, err := readReport(path, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	err = ValidateReport([]byte(report))
+	if err != nil {
+		return "", err
+	}
+
+	return report, nil
+}
+
+// clusterNameFromReportFile function retrieves cluster name from name of file
+// with cluster report. Error is returned when the cluster name is not a
+// proper UUID.
+func clusterNameFromReportFile(fileName string) (types.ClusterName, error) {
+	name := strings.TrimSuffix(strings.TrimPrefix(fileName, reportFilePrefix), reportFileSuffix)
+
+	_, err := uuid.Parse(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid cluster name '%s': %w", name, err)
+	}
+
+	return types.ClusterName(name), nil
+}
+
+// discoverReports function reads all report_*.json files from given
+// directory. All malformed files are reported together in one error.
+func discoverReports(path string) (map[types.ClusterName]string, error) {
+	reports := make(map[types.ClusterName]string)
+
+	fileNames, err := filepath.Glob(filepath.Join(path, reportFilePrefix+"*"+reportFileSuffix))
+	if err != nil {
+		return reports, err
+	}
+
+	// make error messages reproducible
+	sort.Strings(fileNames)
+
+	var malformed []error
+
+	for _, fileName := range fileNames {
+		baseName := filepath.Base(fileName)
+
+		clusterName, err := clusterNameFromReportFile(baseName)
+		if err != nil {
+			malformed = append(malformed, fmt.Errorf("%s: %w", baseName, err))
+			continue
+		}
+
+		report, err := readAndValidateReport(path, baseName)
+		if err != nil {
+			malformed = append(malformed, fmt.Errorf("%s: %w", baseName, err))
+			continue
+		}
+
+		reports[clusterName] = report
+	}
+
+	if len(malformed) > 0 {
+		return reports, malformedReportsError(path, malformed)
+	}
+
+	return reports, nil
+}
+
+// malformedReportsError constructs one error that contains list of all
+// malformed report files
+func malformedReportsError(path string, malformed []error) error {
+	return fmt.Errorf("%d malformed report file(s) found in directory %s:\n%w",
+		len(malformed), path, errors.Join(malformed...))
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// Request IDs of on demand data gathering. Each request ID is received at
+// given time, then it is processing for configured period and then it is
+// processed. Initial request IDs and their rule hits are taken from data
+// package when storage is constructed, so they are processed already and they
+// are never forgotten. New request IDs are added when data gathering is
+// triggered and they are forgotten when they are older than retention period.
+// Request IDs are kept when mock data are reloaded.
+
+import (
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/data"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// States of request ID
+const (
+	RequestStatusReceived   = "received"
+	RequestStatusProcessing = "processing"
+	RequestStatusProcessed  = "processed"
+	RequestStatusUnknown    = "unknown"
+)
+
+// DefaultRequestRetention is used when request ID retention is not
+// configured
+const DefaultRequestRetention = 24 * time.Hour
+
+// initialRequestsInterval is time between initial request IDs of one cluster
+const initialRequestsInterval = time.Minute
+
+// RequestsConfiguration represents configuration of request IDs lifecycle.
+// Received delay is time between receiving request ID and start of its
+// processing, processing delay is time needed to process it. Request IDs
+// older than retention are forgotten.
+type RequestsConfiguration struct {
+	ReceivedDelay   time.Duration `mapstructure:"received_delay" toml:"received_delay"`
+	ProcessingDelay time.Duration `mapstructure:"processing_delay" toml:"processing_delay"`
+	Retention       time.Duration `mapstructure:"retention" toml:"retention"`
+}
+
+// RequestStorage represents storage of request IDs for clusters
+type RequestStorage interface {
+	SetRequestsConfiguration(config RequestsConfiguration)
+	ListOfRequestsForCluster(clusterName types.ClusterName) ([]types.RequestInfo, bool)
+	GetRequestForCluster(clusterName types.ClusterName, requestID types.RequestID) (types.RequestInfo, bool)
+	AddRequestForCluster(clusterName types.ClusterName, requestID types.RequestID,
+		ruleHits []types.SimplifiedRuleHit) types.RequestInfo
+}
+
+// requestRecord represents one request ID together with time when it was
+// received and rule hits that are available when it is processed. Initial
+// request IDs are marked as fixtures, so they do not expire.
+type requestRecord struct {
+	requestID types.RequestID
+	received  time.Time
+	ruleHits  []types.SimplifiedRuleHit
+	fixture   bool
+}
+
+// initialRequests function prepares request IDs taken from data package.
+// Request IDs of one cluster are received in sequence and all of them are
+// processed at given time.
+func initialRequests(now time.Time, config RequestsConfiguration) map[types.ClusterName][]requestRecord {
+	requests := make(map[types.ClusterName][]requestRecord, len(data.RequestIDs))
+	processedAt := now.Add(-config.ReceivedDelay - config.ProcessingDelay)
+
+	for clusterName, requestIDs := range data.RequestIDs {
+		records := make([]requestRecord, len(requestIDs))
+		for i, requestID := range requestIDs {
+			records[i] = requestRecord{
+				requestID: requestID,
+				received:  processedAt.Add(-time.Duration(len(requestIDs)-i) * initialRequestsInterval),
+				ruleHits:  data.SimplifiedRuleHits[clusterName][requestID],
+				fixture:   true,
+			}
+		}
+		requests[clusterName] = records
+	}
+	return requests
+}
+
+// retention returns configured retention of request IDs
+func (config RequestsConfiguration) retention() time.Duration {
+	if config.Retention <= 0 {
+		return DefaultRequestRetention
+	}
+	return config.Retention
+}
+
+// requestInfo returns state and timestamps of request ID at given time
+func (config RequestsConfiguration) requestInfo(record requestRecord, now time.Time) types.RequestInfo {
+	processingAt := record.received.Add(config.ReceivedDelay)
+	processedAt := processingAt.Add(config.ProcessingDelay)
+
+	info := types.RequestInfo{
+		RequestID: record.requestID,
+		Received:  record.received,
+		Status:    RequestStatusReceived,
+	}
+	switch {
+	case !now.Before(processedAt):
+		info.Status = RequestStatusProcessed
+		info.Processed = processedAt
+		info.RuleHits = record.ruleHits
+	case !now.Before(processingAt):
+		info.Status = RequestStatusProcessing
+	}
+	return info
+}
+
+// expireRequests removes request IDs older than retention, except initial
+// ones. It needs to be called with write lock held.
+func (storage *MemoryStorage) expireRequests(now time.Time) {
+	threshold := now.Add(-storage.requestsConfig.retention())
+	for clusterName, records := range storage.requests {
+		kept := records[:0]
+		for _, record := range records {
+			if record.fixture || record.received.After(threshold) {
+				kept = append(kept, record)
+			}
+		}
+		storage.requests[clusterName] = kept
+	}
+}
+
+// SetRequestsConfiguration replaces configuration of request IDs lifecycle.
+// Initial request IDs are prepared again, so they are processed already.
+func (storage *MemoryStorage) SetRequestsConfiguration(config RequestsConfiguration) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	storage.requestsConfig = config
+	storage.requests = initialRequests(time.Now(), config)
+}
+
+// ListOfRequestsForCluster returns all request IDs for given cluster that
+// have not expired yet. False is returned for unknown cluster.
+func (storage *MemoryStorage) ListOfRequestsForCluster(clusterName types.ClusterName) ([]types.RequestInfo, bool) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	now := time.Now()
+	storage.expireRequests(now)
+
+	records, found := storage.requests[clusterName]
+	if !found {
+		return nil, false
+	}
+
+	requests := make([]types.RequestInfo, len(records))
+	for i, record := range records {
+		requests[i] = storage.requestsConfig.requestInfo(record, now)
+	}
+	return requests, true
+}
+
+// GetRequestForCluster returns state of given request ID. Unknown status is
+// returned for request IDs that are not known or that have expired already.
+// False is returned for unknown cluster.
+func (storage *MemoryStorage) GetRequestForCluster(clusterName types.ClusterName, requestID types.RequestID) (
+	types.RequestInfo, bool,
+) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	now := time.Now()
+	storage.expireRequests(now)
+
+	records, found := storage.requests[clusterName]
+	if !found {
+		return types.RequestInfo{}, false
+	}
+
+	for _, record := range records {
+		if record.requestID == requestID {
+			return storage.requestsConfig.requestInfo(record, now), true
+		}
+	}
+	return types.RequestInfo{RequestID: requestID, Status: RequestStatusUnknown}, true
+}
+
+// AddRequestForCluster adds new request ID received now to given cluster.
+// Rule hits are available when the request is processed.
+func (storage *MemoryStorage) AddRequestForCluster(clusterName types.ClusterName, requestID types.RequestID,
+	ruleHits []types.SimplifiedRuleHit,
+) types.RequestInfo {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	record := requestRecord{
+		requestID: requestID,
+		received:  time.Now(),
+		ruleHits:  ruleHits,
+	}
+	storage.requests[clusterName] = append(storage.requests[clusterName], record)
+	return storage.requestsConfig.requestInfo(record, record.received)
+}
@@ -15,12 +15,209 @@
 package storage
 
 import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
 )
 
+// ErrRuleNotFound is returned when rule or its error key is not known
+var ErrRuleNotFound = errors.New("Rule not found")
+
+// rule modules used in reports might contain this suffix
+const ruleModuleSuffix = ".report"
+
+// formats of publish date used in rule content
+var publishDateFormats = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// SetRuleContent replaces rule content used to look up rules
+func (storage *MemoryStorage) SetRuleContent(ruleContent []content.RuleContent) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	storage.ruleContent = ruleContent
+}
+
+// GetRuleContent returns current rule content, including rules added at
+// runtime
+func (storage *MemoryStorage) GetRuleContent() []content.RuleContent {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	ruleContent := make([]content.RuleContent, len(storage.ruleContent))
+	copy(ruleContent, storage.ruleContent)
+	return ruleContent
+}
+
+// AddRule adds new rule into rule content or replaces the existing one
+// with the same rule module
+func (storage *MemoryStorage) AddRule(ruleID types.RuleID, ruleContent content.RuleContent) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	ruleContent.Plugin.PythonModule = string(normalizeRuleID(ruleID))
+	if ruleContent.ErrorKeys == nil {
+		ruleContent.ErrorKeys = make(map[string]content.RuleErrorKeyContent)
+	}
+
+	// slice returned to callers must not be changed
+	updated := make([]content.RuleContent, 0, len(storage.ruleContent)+1)
+	replaced := false
+	for _, existing := range storage.ruleContent {
+		if normalizeRuleID(types.RuleID(existing.Plugin.PythonModule)) == normalizeRuleID(ruleID) {
+			updated = append(updated, ruleContent)
+			replaced = true
+			continue
+		}
+		updated = append(updated, existing)
+	}
+	if !replaced {
+		updated = append(updated, ruleContent)
+	}
+	storage.ruleContent = updated
+}
+
+// DeleteRule deletes rule from rule content
+func (storage *MemoryStorage) DeleteRule(ruleID types.RuleID) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	updated := make([]content.RuleContent, 0, len(storage.ruleContent))
+	for _, existing := range storage.ruleContent {
+		if normalizeRuleID(types.RuleID(existing.Plugin.PythonModule)) != normalizeRuleID(ruleID) {
+			updated = append(updated, existing)
+		}
+	}
+	if len(updated) == len(storage.ruleContent) {
+		return ErrRuleNotFound
+	}
+	storage.ruleContent = updated
+	return nil
+}
+
+// AddRuleErrorKey adds new error key into existing rule or replaces the
+// existing error key
+func (storage *MemoryStorage) AddRuleErrorKey(
+	ruleID types.RuleID, errorKey types.ErrorKey, errorKeyContent content.RuleErrorKeyContent,
+) error {
+	return storage.updateErrorKeys(ruleID, func(errorKeys map[string]content.RuleErrorKeyContent) error {
+		errorKeys[string(errorKey)] = errorKeyContent
+		return nil
+	})
+}
+
+// DeleteRuleErrorKey deletes error key from existing rule
+func (storage *MemoryStorage) DeleteRuleErrorKey(ruleID types.RuleID, errorKey types.ErrorKey) error {
+	return storage.updateErrorKeys(ruleID, func(errorKeys map[string]content.RuleErrorKeyContent) error {
+		if _, found := errorKeys[string(errorKey)]; !found {
+			return ErrRuleNotFound
+		}
+		delete(errorKeys, string(errorKey))
+		return nil
+	})
+}
+
+// updateErrorKeys changes copy of error keys for given rule and replaces the
+// rule content with the changed one
+func (storage *MemoryStorage) updateErrorKeys(
+	ruleID types.RuleID, update func(map[string]content.RuleErrorKeyContent) error,
+) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	for i, existing := range storage.ruleContent {
+		if normalizeRuleID(types.RuleID(existing.Plugin.PythonModule)) != normalizeRuleID(ruleID) {
+			continue
+		}
+
+		errorKeys := make(map[string]content.RuleErrorKeyContent, len(existing.ErrorKeys)+1)
+		for key, value := range existing.ErrorKeys {
+			errorKeys[key] = value
+		}
+		err := update(errorKeys)
+		if err != nil {
+			return err
+		}
+
+		// slice returned to callers must not be changed
+		updated := make([]content.RuleContent, len(storage.ruleContent))
+		copy(updated, storage.ruleContent)
+		updated[i].ErrorKeys = errorKeys
+		storage.ruleContent = updated
+		return nil
+	}
+	return ErrRuleNotFound
+}
+
+// normalizeRuleID removes the optional suffix from rule module
+func normalizeRuleID(ruleID types.RuleID) types.RuleID {
+	return types.RuleID(strings.TrimSuffix(string(ruleID), ruleModuleSuffix))
+}
+
+// findRuleContent finds content for given rule
+func (storage *MemoryStorage) findRuleContent(ruleID types.RuleID) (content.RuleContent, bool) {
+	ruleID = normalizeRuleID(ruleID)
+	for _, ruleContent := range storage.ruleContent {
+		if normalizeRuleID(types.RuleID(ruleContent.Plugin.PythonModule)) == ruleID {
+			return ruleContent, true
+		}
+	}
+	return content.RuleContent{}, false
+}
+
+// parsePublishDate parses publish date stored in error key metadata. Zero
+// time is returned for missing or unknown date.
+func parsePublishDate(publishDate string) time.Time {
+	for _, format := range publishDateFormats {
+		parsed, err := time.Parse(format, publishDate)
+		if err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
+// firstNonEmpty returns error key specific text if it is set, or the text
+// common for all error keys otherwise
+func firstNonEmpty(errorKeyText, ruleText string) string {
+	if errorKeyText != "" {
+		return errorKeyText
+	}
+	return ruleText
+}
+
 // GetRuleWithContent returns rule with content for provided ruleID and ruleErrorKey
-func (storage MemoryStorage) GetRuleWithContent(_ types.RuleID, _ types.ErrorKey) (*types.RuleWithContent, error) {
-	var result types.RuleWithContent
+func (storage *MemoryStorage) GetRuleWithContent(
+	ruleID types.RuleID, ruleErrorKey types.ErrorKey,
+) (*types.RuleWithContent, error) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	ruleContent, found := storage.findRuleContent(ruleID)
+	if !found {
+		return nil, ErrRuleNotFound
+	}
+
+	errorKey, found := ruleContent.ErrorKeys[string(ruleErrorKey)]
+	if !found {
+		return nil, ErrRuleNotFound
+	}
 
-	return &result, nil
+	return &types.RuleWithContent{
+		Module:      normalizeRuleID(ruleID),
+		Name:        ruleContent.Plugin.Name,
+		Summary:     firstNonEmpty(errorKey.Summary, ruleContent.Summary),
+		Reason:      firstNonEmpty(errorKey.Reason, ruleContent.Reason),
+		Resolution:  firstNonEmpty(errorKey.Resolution, ruleContent.Resolution),
+		MoreInfo:    firstNonEmpty(errorKey.MoreInfo, ruleContent.MoreInfo),
+		ErrorKey:    ruleErrorKey,
+		Description: errorKey.Metadata.Description,
+		TotalRisk:   errorKey.TotalRisk,
+		PublishDate: parsePublishDate(errorKey.Metadata.PublishDate),
+		Active:      errorKey.Metadata.Status == "active",
+		Generic:     firstNonEmpty(errorKey.Generic, ruleContent.Generic),
+		Tags:        errorKey.Metadata.Tags,
+	}, nil
 }
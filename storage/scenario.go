@@ -0,0 +1,189 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// Methods to change mock data at runtime. They are used by debug endpoints
+// to prepare exact scenarios for integration tests. All changes are kept in
+// memory only and are discarded when mock data are reloaded.
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// WriteReportForCluster stores new report for given cluster or replaces the
+// existing one. Report rotation is switched off for changing clusters.
+func (storage *MemoryStorage) WriteReportForCluster(clusterName types.ClusterName, report types.ClusterReport) error {
+	err := ValidateReport([]byte(report))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedReport, err)
+	}
+
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	if storage.reports == nil {
+		storage.reports = make(map[types.ClusterName]string)
+	}
+	storage.reports[clusterName] = string(report)
+	delete(storage.changingClusters, clusterName)
+
+	log.Info().Str("cluster", string(clusterName)).Msg("Report written")
+	return nil
+}
+
+// DeleteReportForCluster deletes report for given cluster. The cluster
+// remains assigned to its organization.
+func (storage *MemoryStorage) DeleteReportForCluster(clusterName types.ClusterName) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	_, found := storage.reports[clusterName]
+	_, changing := storage.changingClusters[clusterName]
+	if !found && !changing {
+		return ErrClusterNotFound
+	}
+
+	delete(storage.reports, clusterName)
+	delete(storage.changingClusters, clusterName)
+
+	log.Info().Str("cluster", string(clusterName)).Msg("Report deleted")
+	return nil
+}
+
+// AddClusterToOrg assigns cluster to given organization. The cluster is
+// removed from all other organizations and the organization is created when
+// it does not exist yet.
+func (storage *MemoryStorage) AddClusterToOrg(orgID types.OrgID, clusterName types.ClusterName) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	if storage.isForbiddenOrg(orgID) {
+		return ErrForbiddenOrganization
+	}
+
+	if storage.clusterBelongsToOrg(orgID, clusterName) {
+		return nil
+	}
+
+	storage.removeClusterFromAllOrgs(clusterName)
+
+	if storage.clusters == nil {
+		storage.clusters = make(map[types.OrgID][]types.ClusterName)
+	}
+	if _, found := storage.clusters[orgID]; !found {
+		storage.orgs = append(storage.orgs, orgID)
+	}
+	storage.clusters[orgID] = append(storage.clusters[orgID], clusterName)
+
+	log.Info().
+		Int("organization", int(orgID)).
+		Str("cluster", string(clusterName)).
+		Msg("Cluster added to organization")
+	return nil
+}
+
+// RemoveClusterFromOrg removes cluster from given organization. Report for
+// the cluster is kept.
+func (storage *MemoryStorage) RemoveClusterFromOrg(orgID types.OrgID, clusterName types.ClusterName) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	if storage.isForbiddenOrg(orgID) {
+		return ErrForbiddenOrganization
+	}
+
+	if !storage.clusterBelongsToOrg(orgID, clusterName) {
+		return ErrClusterNotFound
+	}
+
+	storage.clusters[orgID] = removeCluster(storage.clusters[orgID], clusterName)
+
+	log.Info().
+		Int("organization", int(orgID)).
+		Str("cluster", string(clusterName)).
+		Msg("Cluster removed from organization")
+	return nil
+}
+
+// DeleteOrganizations deletes given organizations together with all their
+// clusters and reports. Unknown organizations are ignored.
+func (storage *MemoryStorage) DeleteOrganizations(orgIDs []types.OrgID) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	for _, orgID := range orgIDs {
+		for _, clusterName := range storage.clusters[orgID] {
+			storage.deleteClusterData(clusterName)
+		}
+		delete(storage.clusters, orgID)
+		delete(storage.forbiddenOrgs, orgID)
+
+		orgs := storage.orgs[:0]
+		for _, org := range storage.orgs {
+			if org != orgID {
+				orgs = append(orgs, org)
+			}
+		}
+		storage.orgs = orgs
+
+		log.Info().Int("organization", int(orgID)).Msg("Organization deleted")
+	}
+}
+
+// DeleteClusters deletes reports for given clusters and removes the clusters
+// from all organizations. Unknown clusters are ignored.
+func (storage *MemoryStorage) DeleteClusters(clusterNames []types.ClusterName) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	for _, clusterName := range clusterNames {
+		storage.removeClusterFromAllOrgs(clusterName)
+		storage.deleteClusterData(clusterName)
+
+		log.Info().Str("cluster", string(clusterName)).Msg("Cluster deleted")
+	}
+}
+
+// deleteClusterData deletes report, display name and report rotation for
+// given cluster
+func (storage *MemoryStorage) deleteClusterData(clusterName types.ClusterName) {
+	delete(storage.reports, clusterName)
+	delete(storage.displayNames, clusterName)
+	delete(storage.changingClusters, clusterName)
+}
+
+// removeClusterFromAllOrgs removes cluster from all organizations it belongs to
+func (storage *MemoryStorage) removeClusterFromAllOrgs(clusterName types.ClusterName) {
+	for orgID, clusters := range storage.clusters {
+		storage.clusters[orgID] = removeCluster(clusters, clusterName)
+	}
+}
+
+// removeCluster returns list of clusters without the selected one
+func removeCluster(clusters []types.ClusterName, clusterName types.ClusterName) []types.ClusterName {
+	result := make([]types.ClusterName, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster != clusterName {
+			result = append(result, cluster)
+		}
+	}
+	return result
+}
@@ -22,45 +22,115 @@ package storage
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
 
+	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
 	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
 )
 
 const clusterNotFoundMessage = "Cluster not found"
 
+const noPermissionsForOrg = "You have no permissions to get or change info about this organization"
+
+var (
+	// ErrClusterNotFound is returned when cluster or its report is not known
+	ErrClusterNotFound = errors.New(clusterNotFoundMessage)
+
+	// ErrForbiddenOrganization is returned when organization can not be
+	// accessed at all
+	ErrForbiddenOrganization = errors.New(noPermissionsForOrg)
+
+	// ErrMalformedReport is returned when report to be stored does not
+	// have the expected format
+	ErrMalformedReport = errors.New("malformed report")
+)
+
 // Storage represents an interface to almost any database or storage system
 type Storage interface {
+	AckStorage
+	DVOStorage
+	RequestStorage
 	Init() error
 	Close() error
 	ListOfOrgs() ([]types.OrgID, error)
 	ListOfClustersForOrg(orgID types.OrgID) ([]types.ClusterName, error)
 	ReadReportForCluster(clusterName types.ClusterName) (types.ClusterReport, error)
 	ReadReportForOrganizationAndCluster(orgID types.OrgID, clusterName types.ClusterName) (types.ClusterReport, error)
+	GetClusterDisplayName(clusterName types.ClusterName) string
+	GetOrgForCluster(clusterName types.ClusterName) (types.OrgID, error)
+	Reload(ruleContent []content.RuleContent) error
+	WriteReportForCluster(clusterName types.ClusterName, report types.ClusterReport) error
+	DeleteReportForCluster(clusterName types.ClusterName) error
+	AddClusterToOrg(orgID types.OrgID, clusterName types.ClusterName) error
+	RemoveClusterFromOrg(orgID types.OrgID, clusterName types.ClusterName) error
+	DeleteOrganizations(orgIDs []types.OrgID)
+	DeleteClusters(clusterNames []types.ClusterName)
+	VoteOnRule(clusterName types.ClusterName, ruleID types.RuleID, userID types.UserID, vote types.UserVote)
+	GetUserVote(clusterName types.ClusterName, ruleID types.RuleID, userID types.UserID) types.UserVote
+	GetUserVotesForCluster(clusterName types.ClusterName, userID types.UserID) map[types.RuleID]types.UserVote
+	DisableRuleForCluster(clusterName types.ClusterName, ruleID types.RuleID, justification string)
+	EnableRuleForCluster(clusterName types.ClusterName, ruleID types.RuleID)
+	ListOfDisabledRulesForCluster(clusterName types.ClusterName) []DisabledRule
+	SetRuleContent(ruleContent []content.RuleContent)
+	GetRuleContent() []content.RuleContent
+	AddRule(ruleID types.RuleID, ruleContent content.RuleContent)
+	DeleteRule(ruleID types.RuleID) error
+	AddRuleErrorKey(ruleID types.RuleID, errorKey types.ErrorKey, errorKeyContent content.RuleErrorKeyContent) error
+	DeleteRuleErrorKey(ruleID types.RuleID, errorKey types.ErrorKey) error
 	GetRuleWithContent(ruleID types.RuleID, ruleErrorKey types.ErrorKey) (*types.RuleWithContent, error)
 	GetPredictionForCluster(cluster types.ClusterName) (*types.UpgradeRiskPrediction, error)
+	GetPredictionHistoryForCluster(cluster types.ClusterName, from, to time.Time) (
+		[]types.UpgradeRiskPredictionHistoryItem, error,
+	)
 }
 
 // MemoryStorage data structure represents configuration of memory storage used
-// to store mock data.
+// to store mock data. All organizations, clusters and reports are read from
+// catalog file stored in mock data directory. Data changed by users (votes,
+// disabled rules, acks, request IDs etc.) are kept when mock data are
+// reloaded.
 type MemoryStorage struct {
+	path  string
+	mutex sync.RWMutex
+	mockData
+	votes         map[voteKey]types.UserVote
+	disabledRules map[disabledRuleKey]DisabledRule
+	ruleContent   []content.RuleContent
+	acks          map[ackKey]types.Acknowledge
+
+	requestsConfig RequestsConfiguration
+	requests       map[types.ClusterName][]requestRecord
+}
+
+// mockData represents all data read from files stored in mock data
+// directory. The whole structure is replaced when mock data are reloaded.
+type mockData struct {
+	orgs             []types.OrgID
+	forbiddenOrgs    map[types.OrgID]struct{}
+	clusters         map[types.OrgID][]types.ClusterName
+	displayNames     map[types.ClusterName]string
+	reports          map[types.ClusterName]string
+	changingClusters map[types.ClusterName][]types.ClusterName
+	dvoChecks        map[string]DVOCheck
+	dvoClusters      map[types.ClusterName]DVOCluster
+	dvoWorkloads     map[types.ClusterName][]types.DVOWorkload
+	upgradeRisks     map[types.ClusterName]UpgradeRiskEntry
 }
 
 // Special clusters can change results in given time period, for example each
 // 10 minutes or so. This is to simulate real world behaviour.
 const changingClustersPeriodInMinutes = 15
 
-const noPermissionsForOrg = "You have no permissions to get or change info about this organization"
-
-var reports = make(map[string]string)
-
-func readReport(path, clusterName string) (string, error) {
-	absPath, err := filepath.Abs(path + "/report_" + clusterName + ".json")
+func readReport(path, fileName string) (string, error) {
+	absPath, err := filepath.Abs(filepath.Join(path, fileName))
 	if err != nil {
 		return "", err
 	}
@@ -77,68 +147,159 @@ func init() {
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 }
 
-func initStorage(path string) error {
-	clusters := []string{
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a266", //nolint:goconst
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a267",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a268",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a269",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26a",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26b",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26c",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26d",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26e",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26f",
-		"74ae54aa-6577-4e80-85e7-697cb646ff37", //nolint:goconst
-		"a7467445-8d6a-43cc-b82c-7007664bdf69", //nolint:goconst
-		"ee7d2bf4-8933-4a3a-8634-3328fe806e08", //nolint:goconst
-		"eeeeeeee-eeee-eeee-eeee-000000000001", //nolint:goconst
-		"00000001-624a-49a5-bab8-4fdc5e51a266",
-		"00000001-624a-49a5-bab8-4fdc5e51a267",
-		"00000001-624a-49a5-bab8-4fdc5e51a268",
-		"00000001-624a-49a5-bab8-4fdc5e51a269",
-		"00000001-624a-49a5-bab8-4fdc5e51a26a",
-		"00000001-624a-49a5-bab8-4fdc5e51a26b",
-		"00000001-624a-49a5-bab8-4fdc5e51a26c",
-		"00000001-624a-49a5-bab8-4fdc5e51a26d",
-		"00000001-624a-49a5-bab8-4fdc5e51a26e",
-		"00000001-624a-49a5-bab8-4fdc5e51a26f",
-		"00000001-6577-4e80-85e7-697cb646ff37",
-		"00000001-8933-4a3a-8634-3328fe806e08",
-		"00000001-8d6a-43cc-b82c-7007664bdf69",
-		"00000001-eeee-eeee-eeee-000000000001",
-		"00000002-624a-49a5-bab8-4fdc5e51a266",
-		"00000002-6577-4e80-85e7-697cb646ff37",
-		"00000002-8933-4a3a-8634-3328fe806e08",
-		"00000003-8933-4a3a-8634-3328fe806e08",
-		"00000003-8d6a-43cc-b82c-7007664bdf69",
-		"00000003-eeee-eeee-eeee-000000000001",
+// readFixtureFile function reads and parses optional YAML fixture file stored
+// in given directory. The output value is not changed when the file does not
+// exist.
+func readFixtureFile(path, fileName string, out interface{}) error {
+	fileName = filepath.Join(path, fileName)
+
+	// disable "G304 (CWE-22): Potential file inclusion via variable"
+	content, err := os.ReadFile(fileName) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
 	}
-	for _, cluster := range clusters {
-		report, err := readReport(path, cluster)
-		if err != nil {
-			return err
-		}
-		reports[cluster] = report
+	if err != nil {
+		return err
+	}
+
+	err = yaml.Unmarshal(content, out)
+	if err != nil {
+		return fmt.Errorf("unable to parse fixture file %s: %w", fileName, err)
 	}
 	return nil
 }
 
+// readMockData function reads catalog file, all reports referenced from it,
+// all other reports found in mock data directory, and DVO data
+func readMockData(path string) (mockData, error) {
+	var data mockData
+
+	catalog, err := ReadCatalog(path)
+	if err != nil {
+		return data, err
+	}
+
+	data.orgs = make([]types.OrgID, 0, len(catalog.Organizations))
+	data.forbiddenOrgs = make(map[types.OrgID]struct{})
+	data.clusters = make(map[types.OrgID][]types.ClusterName)
+	data.displayNames = make(map[types.ClusterName]string)
+
+	// all report_*.json files are registered automatically, even if they
+	// are not mentioned in catalog
+	reports, err := discoverReports(path)
+
+	// continue with catalog to report all problems at once
+	var malformed []error
+	if err != nil {
+		malformed = append(malformed, err)
+	}
+
+	for _, org := range catalog.Organizations {
+		data.orgs = append(data.orgs, org.OrgID)
+		if org.Forbidden {
+			data.forbiddenOrgs[org.OrgID] = struct{}{}
+		}
+
+		clusters := make([]types.ClusterName, 0, len(org.Clusters))
+		for _, cluster := range org.Clusters {
+			clusters = append(clusters, cluster.Name)
+			data.displayNames[cluster.Name] = cluster.DisplayName
+
+			// report stored in file with default name has been read already
+			fileName := cluster.ReportFile()
+			if _, found := reports[cluster.Name]; found && fileName == reportFileName(cluster.Name) {
+				continue
+			}
+
+			report, err := readAndValidateReport(path, fileName)
+			if err != nil {
+				malformed = append(malformed, fmt.Errorf("%s (cluster %s): %w", fileName, cluster.Name, err))
+				continue
+			}
+			reports[cluster.Name] = report
+		}
+		data.clusters[org.OrgID] = clusters
+	}
+
+	if len(malformed) > 0 {
+		return data, errors.Join(malformed...)
+	}
+
+	// all variants of changing clusters need to be known
+	for changingCluster, variants := range catalog.ChangingClusters {
+		for _, variant := range variants {
+			if _, found := reports[variant]; !found {
+				return data, fmt.Errorf("unknown report variant %s for changing cluster %s", variant, changingCluster)
+			}
+		}
+	}
+	data.changingClusters = catalog.ChangingClusters
+	data.reports = reports
+
+	err = readDVOData(path, &data)
+	if err != nil {
+		return data, err
+	}
+
+	data.upgradeRisks, err = readUpgradeRisks(path)
+	if err != nil {
+		return data, err
+	}
+
+	log.Info().
+		Int("organizations", len(data.orgs)).
+		Int("reports", len(data.reports)).
+		Int("changing clusters", len(data.changingClusters)).
+		Int("DVO clusters", len(data.dvoWorkloads)).
+		Int("upgrade risks predictions", len(data.upgradeRisks)).
+		Msg("Catalog read")
+	return data, nil
+}
+
 // New function creates and initializes a new instance of Storage interface
 func New(path string) (*MemoryStorage, error) {
-	err := initStorage(path)
-	return &MemoryStorage{}, err
+	data, err := readMockData(path)
+	storage := &MemoryStorage{
+		path:     path,
+		mockData: data,
+		requests: initialRequests(time.Now(), RequestsConfiguration{}),
+	}
+	if err != nil {
+		return storage, err
+	}
+
+	storage.acks, err = readAcks(path)
+	return storage, err
+}
+
+// Reload method reads all mock data again and replaces the current ones
+// together with rule content. The current data are kept untouched when any
+// error is detected.
+func (storage *MemoryStorage) Reload(ruleContent []content.RuleContent) error {
+	data, err := readMockData(storage.path)
+	if err != nil {
+		return err
+	}
+
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	storage.mockData = data
+	storage.ruleContent = ruleContent
+
+	log.Info().Str("path", storage.path).Msg("Mock data reloaded")
+	return nil
 }
 
 // Init performs all database initialization
 // tasks necessary for further service operation.
-func (storage MemoryStorage) Init() error {
+func (storage *MemoryStorage) Init() error {
 	log.Info().Msg("Initializing connection to data storage")
 	return nil
 }
 
 // Close method closes the connection to database. Needs to be called at the end of application lifecycle.
-func (storage MemoryStorage) Close() error {
+func (storage *MemoryStorage) Close() error {
 	log.Info().Msg("Closing connection to data storage")
 	return nil
 }
@@ -156,75 +317,65 @@ type Report struct {
 }
 
 // ListOfOrgs reads list of all organizations that have at least one cluster report
-func (storage MemoryStorage) ListOfOrgs() ([]types.OrgID, error) {
-	orgs := []types.OrgID{
-		11789772,
-		11940171,
-	}
+func (storage *MemoryStorage) ListOfOrgs() ([]types.OrgID, error) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	orgs := make([]types.OrgID, len(storage.orgs))
+	copy(orgs, storage.orgs)
 	return orgs, nil
 }
 
-func clustersForOrganization11789772() []types.ClusterName {
-	clusters := make([]types.ClusterName, 0, 14)
-	clusters = append(clusters,
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a266", //nolint:goconst
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a267",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a268",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a269",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26a",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26b",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26c",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26d",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26e",
-		"34c3ecc5-624a-49a5-bab8-4fdc5e51a26f",
-		"74ae54aa-6577-4e80-85e7-697cb646ff37", //nolint:goconst
-		"a7467445-8d6a-43cc-b82c-7007664bdf69", //nolint:goconst
-		"ee7d2bf4-8933-4a3a-8634-3328fe806e08", //nolint:goconst
-		"eeeeeeee-eeee-eeee-eeee-000000000001") //nolint:goconst
-	return clusters
+// isForbiddenOrg checks if the organization can not be accessed at all
+func (storage *MemoryStorage) isForbiddenOrg(orgID types.OrgID) bool {
+	_, forbidden := storage.forbiddenOrgs[orgID]
+	return forbidden
 }
 
 // ListOfClustersForOrg reads list of all clusters fro given organization
-func (storage MemoryStorage) ListOfClustersForOrg(orgID types.OrgID) ([]types.ClusterName, error) {
-	clusters := make([]types.ClusterName, 0)
-	switch orgID {
-	case 11940171:
-		return clusters, errors.New(noPermissionsForOrg)
-	case 11789772:
-		return clustersForOrganization11789772(), nil
-	case 1:
-		clusters = append(clusters,
-			"00000001-624a-49a5-bab8-4fdc5e51a266",
-			"00000001-624a-49a5-bab8-4fdc5e51a267",
-			"00000001-624a-49a5-bab8-4fdc5e51a268",
-			"00000001-624a-49a5-bab8-4fdc5e51a269",
-			"00000001-624a-49a5-bab8-4fdc5e51a26a",
-			"00000001-624a-49a5-bab8-4fdc5e51a26b",
-			"00000001-624a-49a5-bab8-4fdc5e51a26c",
-			"00000001-624a-49a5-bab8-4fdc5e51a26d",
-			"00000001-624a-49a5-bab8-4fdc5e51a26e",
-			"00000001-624a-49a5-bab8-4fdc5e51a26f",
-			"00000001-6577-4e80-85e7-697cb646ff37",
-			"00000001-8933-4a3a-8634-3328fe806e08",
-			"00000001-8d6a-43cc-b82c-7007664bdf69",
-			"00000001-eeee-eeee-eeee-000000000001")
-	case 2:
-		clusters = append(clusters,
-			"00000002-624a-49a5-bab8-4fdc5e51a266",
-			"00000002-6577-4e80-85e7-697cb646ff37",
-			"00000002-8933-4a3a-8634-3328fe806e08")
-	case 3:
-		clusters = append(clusters,
-			"00000003-8933-4a3a-8634-3328fe806e08",
-			"00000003-8d6a-43cc-b82c-7007664bdf69",
-			"00000003-eeee-eeee-eeee-000000000001")
+func (storage *MemoryStorage) ListOfClustersForOrg(orgID types.OrgID) ([]types.ClusterName, error) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	if storage.isForbiddenOrg(orgID) {
+		return make([]types.ClusterName, 0), ErrForbiddenOrganization
 	}
 
+	// unknown organization has no clusters
+	clusters := make([]types.ClusterName, len(storage.clusters[orgID]))
+	copy(clusters, storage.clusters[orgID])
+
 	return clusters, nil
 }
 
-func getReportForCluster(clusterName types.ClusterName) (string, bool) {
-	report, ok := reports[string(clusterName)]
+// GetClusterDisplayName returns display name for given cluster. Cluster name
+// is returned for clusters without display name set in catalog.
+func (storage *MemoryStorage) GetClusterDisplayName(clusterName types.ClusterName) string {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	displayName, found := storage.displayNames[clusterName]
+	if !found || displayName == "" {
+		return string(clusterName)
+	}
+	return displayName
+}
+
+// GetOrgForCluster returns ID of organization that owns given cluster
+func (storage *MemoryStorage) GetOrgForCluster(clusterName types.ClusterName) (types.OrgID, error) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	for orgID := range storage.clusters {
+		if storage.clusterBelongsToOrg(orgID, clusterName) {
+			return orgID, nil
+		}
+	}
+	return 0, ErrClusterNotFound
+}
+
+func (storage *MemoryStorage) getReportForCluster(clusterName types.ClusterName) (string, bool) {
+	report, ok := storage.reports[clusterName]
 	if !ok {
 		return "", false
 	}
@@ -232,52 +383,33 @@ func getReportForCluster(clusterName types.ClusterName) (string, bool) {
 }
 
 // ReadReportForCluster reads result (health status) for selected cluster
-func (storage MemoryStorage) ReadReportForCluster(
+func (storage *MemoryStorage) ReadReportForCluster(
 	clusterName types.ClusterName,
 ) (types.ClusterReport, error) {
-	var report string
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
 
-	// clusters that can change its output (report)
+	reportName := clusterName
+
+	// handling for clusters that can change its report
 	// please note that these clusters have special name:
 	// "cccccccc-cccc-cccc-cccc-{index}"
 	//
 	// Mnemotechnic: c - changing
-	changingClusters := map[string][]string{
-		"cccccccc-cccc-cccc-cccc-000000000001": {
-			"34c3ecc5-624a-49a5-bab8-4fdc5e51a266",  //nolint:goconst
-			"74ae54aa-6577-4e80-85e7-697cb646ff37",  //nolint:goconst
-			"a7467445-8d6a-43cc-b82c-7007664bdf69"}, //nolint:goconst
-		"cccccccc-cccc-cccc-cccc-000000000002": {
-			"74ae54aa-6577-4e80-85e7-697cb646ff37",  //nolint:goconst
-			"a7467445-8d6a-43cc-b82c-7007664bdf69",  //nolint:goconst
-			"ee7d2bf4-8933-4a3a-8634-3328fe806e08"}, //nolint:goconst
-		"cccccccc-cccc-cccc-cccc-000000000003": {
-			"ee7d2bf4-8933-4a3a-8634-3328fe806e08",  //nolint:goconst
-			"ee7d2bf4-8933-4a3a-8634-3328fe806e08",  //nolint:goconst
-			"34c3ecc5-624a-49a5-bab8-4fdc5e51a266"}, //nolint:goconst
-		"cccccccc-cccc-cccc-cccc-000000000004": {
-			"eeeeeeee-eeee-eeee-eeee-000000000001",  //nolint:goconst
-			"eeeeeeee-eeee-eeee-eeee-000000000001",  //nolint:goconst
-			"34c3ecc5-624a-49a5-bab8-4fdc5e51a266"}, //nolint:goconst
-	}
-
-	reportName := clusterName
-
-	// handling for clusters that can change its report
-	if changingCluster, found := changingClusters[string(clusterName)]; found {
+	if changingCluster, found := storage.changingClusters[clusterName]; found {
 		reportName = chooseReport(changingCluster)
 	}
 
-	report, found := getReportForCluster(reportName)
+	report, found := storage.getReportForCluster(reportName)
 	if !found {
-		return types.ClusterReport(""), errors.New(clusterNotFoundMessage)
+		return types.ClusterReport(""), ErrClusterNotFound
 	}
 
 	return types.ClusterReport(report), nil
 }
 
 // chooseReport for "changing cluster"
-func chooseReport(variants []string) types.ClusterName {
+func chooseReport(variants []types.ClusterName) types.ClusterName {
 	const operationName = "changingCluster"
 
 	// first we need to get the minute in hour
@@ -292,37 +424,41 @@ func chooseReport(variants []string) types.ClusterName {
 	// and choose the report according to the index
 	cluster := variants[i]
 	log.Info().Int("Index", i).Msg(operationName)
-	log.Info().Str("Cluster", cluster).Msg(operationName)
-	return types.ClusterName(cluster)
+	log.Info().Str("Cluster", string(cluster)).Msg(operationName)
+	return cluster
+}
+
+// clusterBelongsToOrg checks if given cluster is owned by selected
+// organization
+func (storage *MemoryStorage) clusterBelongsToOrg(orgID types.OrgID, clusterName types.ClusterName) bool {
+	for _, cluster := range storage.clusters[orgID] {
+		if cluster == clusterName {
+			return true
+		}
+	}
+	return false
 }
 
 // ReadReportForOrganizationAndCluster reads result (health status) for
 // selected cluster for given organization
-func (storage MemoryStorage) ReadReportForOrganizationAndCluster(
+func (storage *MemoryStorage) ReadReportForOrganizationAndCluster(
 	orgID types.OrgID, clusterName types.ClusterName,
 ) (types.ClusterReport, error) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
 	var report string
 
-	switch orgID {
-	case 11940171:
-		return types.ClusterReport(report), errors.New(noPermissionsForOrg)
-	case 1, 2, 3, 11789772:
-		report, found := getReportForCluster(clusterName)
+	if storage.isForbiddenOrg(orgID) {
+		return types.ClusterReport(report), ErrForbiddenOrganization
+	}
+
+	if storage.clusterBelongsToOrg(orgID, clusterName) {
+		report, found := storage.getReportForCluster(clusterName)
 		if found {
 			return types.ClusterReport(report), nil
 		}
 	}
 
-	return types.ClusterReport(report), errors.New(clusterNotFoundMessage)
-}
-
-// GetPredictionForCluster gets a prediction for the cluster
-func (storage MemoryStorage) GetPredictionForCluster(_ types.ClusterName) (*types.UpgradeRiskPrediction, error) {
-	return &types.UpgradeRiskPrediction{
-		Recommended: true,
-		Predictors: types.UpgradeRisksPredictors{
-			Alerts:             []types.Alert{},
-			OperatorConditions: []types.OperatorCondition{},
-		},
-	}, nil
+	return types.ClusterReport(report), ErrClusterNotFound
 }
@@ -14,3 +14,690 @@ See the License for the specific language governing permissions and
 */
 
 package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/content"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/storage"
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+const mockDataPath = "../data"
+
+// TestNewStorageFromCatalog checks that storage is constructed from the
+// catalog file stored in mock data directory
+func TestNewStorageFromCatalog(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	orgs, err := s.ListOfOrgs()
+	assert.NoError(t, err)
+	assert.Contains(t, orgs, types.OrgID(11789772))
+	assert.Contains(t, orgs, types.OrgID(11940171))
+
+	clusters, err := s.ListOfClustersForOrg(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ClusterName{
+		"00000002-624a-49a5-bab8-4fdc5e51a266",
+		"00000002-6577-4e80-85e7-697cb646ff37",
+		"00000002-8933-4a3a-8634-3328fe806e08"}, clusters)
+}
+
+// TestNewStorageMissingCatalog checks that missing catalog file is reported
+func TestNewStorageMissingCatalog(t *testing.T) {
+	_, err := storage.New(t.TempDir())
+	assert.Error(t, err)
+}
+
+// TestListOfClustersForForbiddenOrg checks the organization marked as
+// forbidden in catalog
+func TestListOfClustersForForbiddenOrg(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	_, err = s.ListOfClustersForOrg(11940171)
+	assert.Error(t, err)
+}
+
+// TestReadReportForOrganizationAndCluster checks that cluster needs to be
+// owned by the organization
+func TestReadReportForOrganizationAndCluster(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	_, err = s.ReadReportForOrganizationAndCluster(2, "00000002-624a-49a5-bab8-4fdc5e51a266")
+	assert.NoError(t, err)
+
+	_, err = s.ReadReportForOrganizationAndCluster(3, "00000002-624a-49a5-bab8-4fdc5e51a266")
+	assert.Error(t, err)
+}
+
+// TestGetClusterDisplayName checks display names read from catalog
+func TestGetClusterDisplayName(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "org2-cluster-01", s.GetClusterDisplayName("00000002-624a-49a5-bab8-4fdc5e51a266"))
+	assert.Equal(t, "ffffffff-ffff-ffff-ffff-000000000001", s.GetClusterDisplayName("ffffffff-ffff-ffff-ffff-000000000001"))
+}
+
+const (
+	emptyCatalog = "organizations: []\n"
+	emptyReport  = `{"report": {"meta": {"count": 0, "last_checked_at": "2020-05-27T09:18:29Z"}, "data": []}, "status": "ok"}`
+)
+
+// writeMockFile writes a file with given content into test directory
+func writeMockFile(t *testing.T, dir, name, content string) {
+	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+	assert.NoError(t, err)
+}
+
+// TestReportAutoDiscovery checks that report files not mentioned in catalog
+// are registered as well
+func TestReportAutoDiscovery(t *testing.T) {
+	dir := t.TempDir()
+	writeMockFile(t, dir, storage.CatalogFileName, emptyCatalog)
+	writeMockFile(t, dir, "report_aaaaaaaa-bbbb-cccc-dddd-000000000001.json", emptyReport)
+
+	s, err := storage.New(dir)
+	assert.NoError(t, err)
+
+	report, err := s.ReadReportForCluster("aaaaaaaa-bbbb-cccc-dddd-000000000001")
+	assert.NoError(t, err)
+	assert.Equal(t, types.ClusterReport(emptyReport), report)
+}
+
+// TestMalformedReports checks that all malformed report files are listed in
+// the error returned from storage constructor
+func TestMalformedReports(t *testing.T) {
+	dir := t.TempDir()
+	writeMockFile(t, dir, storage.CatalogFileName, emptyCatalog)
+	writeMockFile(t, dir, "report_aaaaaaaa-bbbb-cccc-dddd-000000000001.json", emptyReport)
+	writeMockFile(t, dir, "report_aaaaaaaa-bbbb-cccc-dddd-000000000002.json", `{"repors": {}, "status": "ok"}`)
+	writeMockFile(t, dir, "report_aaaaaaaa-bbbb-cccc-dddd-000000000003.json", `not a JSON`)
+	writeMockFile(t, dir, "report_not-an-uuid.json", emptyReport)
+
+	_, err := storage.New(dir)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "000000000001")
+	assert.Contains(t, err.Error(), "report_aaaaaaaa-bbbb-cccc-dddd-000000000002.json")
+	assert.Contains(t, err.Error(), "report_aaaaaaaa-bbbb-cccc-dddd-000000000003.json")
+	assert.Contains(t, err.Error(), "report_not-an-uuid.json")
+}
+
+// TestValidateReport checks the report format validation
+func TestValidateReport(t *testing.T) {
+	assert.NoError(t, storage.ValidateReport([]byte(emptyReport)))
+
+	// missing error key
+	assert.Error(t, storage.ValidateReport([]byte(
+		`{"report": {"meta": {}, "data": [{"rule_id": "rule", "details": {}}]}, "status": "ok"}`)))
+
+	// missing data part
+	assert.Error(t, storage.ValidateReport([]byte(`{"report": {"meta": {}}, "status": "ok"}`)))
+}
+
+// TestReload checks that new reports are visible after reload and that the
+// current data are kept when reload fails
+func TestReload(t *testing.T) {
+	const cluster = "aaaaaaaa-bbbb-cccc-dddd-000000000001"
+
+	dir := t.TempDir()
+	writeMockFile(t, dir, storage.CatalogFileName, emptyCatalog)
+
+	s, err := storage.New(dir)
+	assert.NoError(t, err)
+
+	_, err = s.ReadReportForCluster(cluster)
+	assert.Error(t, err)
+
+	writeMockFile(t, dir, "report_"+cluster+".json", emptyReport)
+	assert.NoError(t, s.Reload(nil))
+
+	report, err := s.ReadReportForCluster(cluster)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ClusterReport(emptyReport), report)
+
+	writeMockFile(t, dir, "report_"+cluster+".json", "{")
+	assert.Error(t, s.Reload(nil))
+
+	report, err = s.ReadReportForCluster(cluster)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ClusterReport(emptyReport), report)
+}
+
+// TestWriteAndDeleteReport checks that reports can be changed at runtime
+func TestWriteAndDeleteReport(t *testing.T) {
+	const cluster = "aaaaaaaa-bbbb-cccc-dddd-000000000001"
+
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	err = s.WriteReportForCluster(cluster, "{")
+	assert.ErrorIs(t, err, storage.ErrMalformedReport)
+
+	assert.NoError(t, s.WriteReportForCluster(cluster, emptyReport))
+	report, err := s.ReadReportForCluster(cluster)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ClusterReport(emptyReport), report)
+
+	assert.NoError(t, s.DeleteReportForCluster(cluster))
+	_, err = s.ReadReportForCluster(cluster)
+	assert.ErrorIs(t, err, storage.ErrClusterNotFound)
+
+	err = s.DeleteReportForCluster(cluster)
+	assert.ErrorIs(t, err, storage.ErrClusterNotFound)
+}
+
+// TestAddAndRemoveClusterFromOrg checks that clusters can be moved between
+// organizations at runtime
+func TestAddAndRemoveClusterFromOrg(t *testing.T) {
+	const cluster = "00000002-624a-49a5-bab8-4fdc5e51a266"
+
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.AddClusterToOrg(42, cluster))
+
+	clusters, err := s.ListOfClustersForOrg(42)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ClusterName{cluster}, clusters)
+
+	// cluster needs to be moved from the original organization
+	clusters, err = s.ListOfClustersForOrg(2)
+	assert.NoError(t, err)
+	assert.NotContains(t, clusters, types.ClusterName(cluster))
+
+	orgs, err := s.ListOfOrgs()
+	assert.NoError(t, err)
+	assert.Contains(t, orgs, types.OrgID(42))
+
+	assert.NoError(t, s.RemoveClusterFromOrg(42, cluster))
+	err = s.RemoveClusterFromOrg(42, cluster)
+	assert.ErrorIs(t, err, storage.ErrClusterNotFound)
+
+	err = s.AddClusterToOrg(11940171, cluster)
+	assert.ErrorIs(t, err, storage.ErrForbiddenOrganization)
+}
+
+// TestDeleteOrganizationsAndClusters checks bulk deletion of mock data
+func TestDeleteOrganizationsAndClusters(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	s.DeleteOrganizations([]types.OrgID{2})
+	orgs, err := s.ListOfOrgs()
+	assert.NoError(t, err)
+	assert.NotContains(t, orgs, types.OrgID(2))
+
+	_, err = s.ReadReportForCluster("00000002-624a-49a5-bab8-4fdc5e51a266")
+	assert.ErrorIs(t, err, storage.ErrClusterNotFound)
+
+	s.DeleteClusters([]types.ClusterName{"00000003-eeee-eeee-eeee-000000000001"})
+	clusters, err := s.ListOfClustersForOrg(3)
+	assert.NoError(t, err)
+	assert.NotContains(t, clusters, types.ClusterName("00000003-eeee-eeee-eeee-000000000001"))
+}
+
+// TestVotes checks that votes are stored per user, cluster and rule
+func TestVotes(t *testing.T) {
+	const (
+		cluster = "00000002-624a-49a5-bab8-4fdc5e51a266"
+		rule    = "ccx_rules_ocp.external.rules.nodes_requirements_check"
+	)
+
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	assert.Equal(t, types.UserVoteNone, s.GetUserVote(cluster, rule, "user1"))
+
+	s.VoteOnRule(cluster, rule, "user1", types.UserVoteLike)
+	s.VoteOnRule(cluster, rule, "user2", types.UserVoteDislike)
+	assert.Equal(t, types.UserVoteLike, s.GetUserVote(cluster, rule, "user1"))
+	assert.Equal(t, types.UserVoteDislike, s.GetUserVote(cluster, rule, "user2"))
+	assert.Equal(t, map[types.RuleID]types.UserVote{rule: types.UserVoteLike},
+		s.GetUserVotesForCluster(cluster, "user1"))
+
+	// votes need to be kept after reload
+	assert.NoError(t, s.Reload(nil))
+	assert.Equal(t, types.UserVoteLike, s.GetUserVote(cluster, rule, "user1"))
+
+	s.VoteOnRule(cluster, rule, "user1", types.UserVoteNone)
+	assert.Equal(t, types.UserVoteNone, s.GetUserVote(cluster, rule, "user1"))
+	assert.Empty(t, s.GetUserVotesForCluster(cluster, "user1"))
+}
+
+// TestDisabledRules checks disabling and enabling rules for clusters
+func TestDisabledRules(t *testing.T) {
+	const (
+		cluster1 = "00000002-624a-49a5-bab8-4fdc5e51a266"
+		cluster2 = "00000002-6577-4e80-85e7-697cb646ff37"
+		rule1    = "ccx_rules_ocp.external.rules.nodes_requirements_check"
+		rule2    = "ccx_rules_ocp.external.bug_rules.bug_1766907"
+	)
+
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	assert.Empty(t, s.ListOfDisabledRulesForCluster(cluster1))
+
+	s.DisableRuleForCluster(cluster1, rule1, "justification1")
+	s.DisableRuleForCluster(cluster1, rule2, "justification2")
+	s.DisableRuleForCluster(cluster2, rule1, "")
+
+	disabledRules := s.ListOfDisabledRulesForCluster(cluster1)
+	assert.Len(t, disabledRules, 2)
+	assert.Equal(t, types.RuleID(rule2), disabledRules[0].RuleID)
+	assert.Equal(t, "justification2", disabledRules[0].Justification)
+	assert.False(t, disabledRules[0].DisabledAt.IsZero())
+	assert.Equal(t, types.RuleID(rule1), disabledRules[1].RuleID)
+
+	s.EnableRuleForCluster(cluster1, rule2)
+	s.EnableRuleForCluster(cluster1, rule2)
+	assert.Len(t, s.ListOfDisabledRulesForCluster(cluster1), 1)
+	assert.Len(t, s.ListOfDisabledRulesForCluster(cluster2), 1)
+}
+
+// testRuleContent is rule content used to check rule lookup
+var testRuleContent = []content.RuleContent{
+	{
+		Plugin: content.RulePluginInfo{
+			Name:         "Nodes requirements",
+			PythonModule: "ccx_rules_ocp.external.rules.nodes_requirements_check",
+		},
+		ErrorKeys: map[string]content.RuleErrorKeyContent{
+			"NODES_MINIMUM_REQUIREMENTS_NOT_MET": {
+				Metadata: content.ErrorKeyMetadata{
+					Description: "Nodes do not meet the minimum requirements",
+					PublishDate: "2020-04-08 00:42:00",
+					Status:      "active",
+					Tags:        []string{"openshift", "performance"},
+				},
+				TotalRisk: 2,
+				Reason:    "error key reason",
+			},
+		},
+		Summary: "rule summary",
+		Reason:  "rule reason",
+	},
+}
+
+// TestGetRuleWithContent checks rule lookup in rule content
+func TestGetRuleWithContent(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	_, err = s.GetRuleWithContent("ccx_rules_ocp.external.rules.nodes_requirements_check", "NODES_MINIMUM_REQUIREMENTS_NOT_MET")
+	assert.ErrorIs(t, err, storage.ErrRuleNotFound)
+
+	s.SetRuleContent(testRuleContent)
+
+	// rule module with .report suffix needs to be accepted too
+	rule, err := s.GetRuleWithContent("ccx_rules_ocp.external.rules.nodes_requirements_check.report", "NODES_MINIMUM_REQUIREMENTS_NOT_MET")
+	assert.NoError(t, err)
+	assert.Equal(t, types.RuleID("ccx_rules_ocp.external.rules.nodes_requirements_check"), rule.Module)
+	assert.Equal(t, "Nodes requirements", rule.Name)
+	assert.Equal(t, "rule summary", rule.Summary)
+	assert.Equal(t, "error key reason", rule.Reason)
+	assert.Equal(t, "Nodes do not meet the minimum requirements", rule.Description)
+	assert.Equal(t, 2, rule.TotalRisk)
+	assert.Equal(t, 2020, rule.PublishDate.Year())
+	assert.True(t, rule.Active)
+	assert.Equal(t, []string{"openshift", "performance"}, rule.Tags)
+
+	_, err = s.GetRuleWithContent("ccx_rules_ocp.external.rules.nodes_requirements_check", "UNKNOWN_KEY")
+	assert.ErrorIs(t, err, storage.ErrRuleNotFound)
+}
+
+// TestAddAndDeleteRules checks that rule content can be changed at runtime
+func TestAddAndDeleteRules(t *testing.T) {
+	const rule = "ccx_rules_ocp.external.rules.new_rule"
+
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+	s.SetRuleContent(testRuleContent)
+
+	s.AddRule(rule+".report", content.RuleContent{Plugin: content.RulePluginInfo{Name: "New rule"}})
+	ruleContent := s.GetRuleContent()
+	assert.Len(t, ruleContent, 2)
+	assert.Equal(t, rule, ruleContent[1].Plugin.PythonModule)
+
+	err = s.AddRuleErrorKey(rule, "NEW_KEY", content.RuleErrorKeyContent{TotalRisk: 3})
+	assert.NoError(t, err)
+	found, err := s.GetRuleWithContent(rule, "NEW_KEY")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, found.TotalRisk)
+	assert.Equal(t, "New rule", found.Name)
+
+	// content returned before the change must not be affected
+	assert.Empty(t, ruleContent[1].ErrorKeys)
+
+	assert.NoError(t, s.DeleteRuleErrorKey(rule, "NEW_KEY"))
+	assert.ErrorIs(t, s.DeleteRuleErrorKey(rule, "NEW_KEY"), storage.ErrRuleNotFound)
+
+	assert.NoError(t, s.DeleteRule(rule))
+	assert.ErrorIs(t, s.DeleteRule(rule), storage.ErrRuleNotFound)
+	assert.ErrorIs(t, s.AddRuleErrorKey(rule, "NEW_KEY", content.RuleErrorKeyContent{}), storage.ErrRuleNotFound)
+	assert.Len(t, s.GetRuleContent(), 1)
+}
+
+// TestAcks checks that acks are read from fixture file and that they are
+// scoped by organization
+func TestAcks(t *testing.T) {
+	const (
+		org1 = 11789772
+		org2 = 1
+		rule = "ccx_rules_ocp.external.rules.nodes_requirements_check.report|NODES_MINIMUM_REQUIREMENTS_NOT_MET"
+	)
+
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	// acks from fixture file
+	assert.Len(t, s.ListOfAcks(org1), 5)
+	assert.Empty(t, s.ListOfAcks(org2))
+
+	ack, found := s.GetAck(org1, rule)
+	assert.True(t, found)
+	assert.Equal(t, "tester1", ack.CreatedBy)
+
+	// existing ack is not changed
+	ack, created := s.AddAck(org1, rule, "new justification", "tester")
+	assert.False(t, created)
+	assert.Equal(t, "Justification1", ack.Justification)
+
+	// the same rule can be acked in other organization
+	ack, created = s.AddAck(org2, rule, "justification", "tester")
+	assert.True(t, created)
+	assert.Equal(t, "tester", ack.CreatedBy)
+	assert.Len(t, s.ListOfAcks(org2), 1)
+
+	justification := "updated"
+	ack, err = s.UpdateAck(org2, rule, &justification)
+	assert.NoError(t, err)
+	assert.Equal(t, justification, ack.Justification)
+
+	ack, found = s.GetAck(org1, rule)
+	assert.True(t, found)
+	assert.Equal(t, "Justification1", ack.Justification)
+
+	assert.NoError(t, s.DeleteAck(org2, rule))
+	assert.ErrorIs(t, s.DeleteAck(org2, rule), storage.ErrAckNotFound)
+	_, err = s.UpdateAck(org2, rule, nil)
+	assert.ErrorIs(t, err, storage.ErrAckNotFound)
+	assert.Len(t, s.ListOfAcks(org1), 5)
+}
+
+// TestMissingAcksFile checks that the fixture file with acks is optional
+func TestMissingAcksFile(t *testing.T) {
+	dir := t.TempDir()
+	writeMockFile(t, dir, storage.CatalogFileName, emptyCatalog)
+
+	s, err := storage.New(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, s.ListOfAcks(1))
+
+	writeMockFile(t, dir, storage.AcksFileName, "not a list")
+	_, err = storage.New(dir)
+	assert.Error(t, err)
+}
+
+// TestGetOrgForCluster checks lookup of organization that owns cluster
+func TestGetOrgForCluster(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	orgID, err := s.GetOrgForCluster("34c3ecc5-624a-49a5-bab8-4fdc5e51a266")
+	assert.NoError(t, err)
+	assert.Equal(t, types.OrgID(11789772), orgID)
+
+	_, err = s.GetOrgForCluster("ffffffff-ffff-ffff-ffff-ffffffffffff")
+	assert.ErrorIs(t, err, storage.ErrClusterNotFound)
+}
+
+// TestDVOData checks that DVO checks and workloads are read from fixture
+// files
+func TestDVOData(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	clusters := s.ListOfDVOClusters()
+	assert.Len(t, clusters, 7)
+	assert.Equal(t, types.ClusterName("00000001-0001-0001-0001-000000000001"), clusters[0])
+
+	workloads, found := s.GetDVOWorkloads(clusters[0])
+	assert.True(t, found)
+	assert.NotEmpty(t, workloads)
+
+	check, found := s.GetDVOCheck("host_network")
+	assert.True(t, found)
+	assert.Equal(t, "2020-04-08T00:42:00Z", check.Modified)
+	assert.NotNil(t, check.TemplateData)
+
+	_, found = s.GetDVOCheck("unknown_check")
+	assert.False(t, found)
+
+	// namespace timestamps are inherited from cluster unless overridden
+	cluster, found := s.GetDVOCluster(clusters[1])
+	assert.True(t, found)
+	assert.Equal(t, types.OrgID(1), cluster.OrgID)
+	assert.Equal(t, "org1-dvo-cluster-02", cluster.DisplayName)
+	assert.Equal(t, "2024-03-02T09:15:00Z", cluster.Namespaces["fbcbe2d3-e398-4b40-9d5e-4eb46fe8286f"].ReportedAt)
+	assert.Equal(t, "2024-03-02T10:00:00Z", cluster.Namespaces["d00b47da-fc6f-4c72-abc1-94f525441c75"].ReportedAt)
+}
+
+// TestMalformedDVOData checks validation of DVO fixture files
+func TestMalformedDVOData(t *testing.T) {
+	dir := t.TempDir()
+	writeMockFile(t, dir, storage.CatalogFileName, emptyCatalog)
+
+	// both files are optional
+	s, err := storage.New(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, s.ListOfDVOClusters())
+
+	writeMockFile(t, dir, storage.DVOChecksFileName, "- check: host_network\n  modified: yesterday\n")
+	_, err = storage.New(dir)
+	assert.Error(t, err)
+
+	writeMockFile(t, dir, storage.DVOChecksFileName, "- check: host_network\n")
+	writeMockFile(t, dir, storage.DVOWorkloadsFileName, `
+- cluster: 00000001-0001-0001-0001-000000000001
+  org_id: 1
+  reported_at: '2024-03-01T10:00:00Z'
+  last_checked_at: '2024-03-01T12:30:00Z'
+  namespaces:
+    - uuid: fbcbe2d3-e398-4b40-9d5e-4eb46fe8286f
+      workloads:
+        - {check: unknown_check, kind: Pod, uid: be466de5-12fb-4710-bf70-62deb38ae563}
+`)
+	_, err = storage.New(dir)
+	assert.Error(t, err)
+
+	// organization is mandatory
+	writeMockFile(t, dir, storage.DVOWorkloadsFileName, `
+- cluster: 00000001-0001-0001-0001-000000000001
+  reported_at: '2024-03-01T10:00:00Z'
+  last_checked_at: '2024-03-01T12:30:00Z'
+`)
+	_, err = storage.New(dir)
+	assert.Error(t, err)
+}
+
+// TestUpgradeRisksPredictions checks that predictions and simulated outcomes
+// are read from fixture file
+func TestUpgradeRisksPredictions(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	prediction, err := s.GetPredictionForCluster("00000001-624a-49a5-bab8-4fdc5e51a266")
+	assert.NoError(t, err)
+	assert.True(t, prediction.Recommended)
+	assert.NotNil(t, prediction.Predictors.Alerts)
+	assert.Empty(t, prediction.Predictors.Alerts)
+
+	prediction, err = s.GetPredictionForCluster("00000003-eeee-eeee-eeee-000000000001")
+	assert.NoError(t, err)
+	assert.False(t, prediction.Recommended)
+	assert.Len(t, prediction.Predictors.Alerts, 3)
+	assert.Len(t, prediction.Predictors.OperatorConditions, 4)
+
+	// URLs are expanded from console URL and names
+	assert.Equal(t, "https://my-cluster.com/monitoring/alerts?orderBy=asc&sortBy=Severity&alert-name=alert1",
+		prediction.Predictors.Alerts[0].URL)
+	assert.Equal(t, "https://my-cluster.com/k8s/cluster/config.openshift.io~v1~ClusterOperator/foc4",
+		prediction.Predictors.OperatorConditions[3].URL)
+
+	_, err = s.GetPredictionForCluster("6cab9726-c2be-438e-af11-db846a678abb")
+	assert.ErrorIs(t, err, storage.ErrClusterManaged)
+
+	_, err = s.GetPredictionForCluster("c60ba611-6af4-4d62-9b9e-36344da5e7bc")
+	assert.ErrorIs(t, err, storage.ErrAMSUnavailable)
+
+	_, err = s.GetPredictionForCluster("897ec1a1-4679-4122-aacb-f0ae9f9e1a5f")
+	assert.ErrorIs(t, err, storage.ErrPredictionUnavailable)
+
+	_, err = s.GetPredictionForCluster("234ec1a1-4679-4122-aacb-f0ae9f9e1a56")
+	assert.ErrorIs(t, err, storage.ErrNoPrediction)
+
+	// clusters not listed in fixture file have no data
+	_, err = s.GetPredictionForCluster("00000000-0000-0000-0000-000000000000")
+	assert.ErrorIs(t, err, storage.ErrNoPrediction)
+}
+
+// TestUpgradeRisksPredictionHistory checks that past predictions are filtered
+// by time range
+func TestUpgradeRisksPredictionHistory(t *testing.T) {
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	const cluster = "00000003-eeee-eeee-eeee-000000000001"
+	history, err := s.GetPredictionHistoryForCluster(cluster, time.Time{}, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, "2024-03-01T10:00:00Z", history[0].LastCheckedAt)
+	assert.True(t, history[0].Recommended)
+	assert.NotNil(t, history[0].Predictors.Alerts)
+	assert.False(t, history[2].Recommended)
+	assert.Equal(t, "https://my-cluster.com/k8s/cluster/config.openshift.io~v1~ClusterOperator/foc2",
+		history[2].Predictors.OperatorConditions[0].URL)
+
+	// both bounds are included
+	from := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC)
+	history, err = s.GetPredictionHistoryForCluster(cluster, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "2024-03-02T10:00:00Z", history[0].LastCheckedAt)
+
+	_, err = s.GetPredictionHistoryForCluster("6cab9726-c2be-438e-af11-db846a678abb", from, to)
+	assert.ErrorIs(t, err, storage.ErrClusterManaged)
+}
+
+// TestMalformedUpgradeRisksPredictions checks validation of upgrade risks
+// fixture file
+func TestMalformedUpgradeRisksPredictions(t *testing.T) {
+	dir := t.TempDir()
+	writeMockFile(t, dir, storage.CatalogFileName, emptyCatalog)
+
+	writeMockFile(t, dir, storage.UpgradeRisksFileName, "- cluster: c1\n  outcome: unknown\n")
+	_, err := storage.New(dir)
+	assert.Error(t, err)
+
+	writeMockFile(t, dir, storage.UpgradeRisksFileName, "- cluster: c1\n- cluster: c1\n")
+	_, err = storage.New(dir)
+	assert.Error(t, err)
+
+	// console URL is needed to expand URL templates
+	writeMockFile(t, dir, storage.UpgradeRisksFileName, "- cluster: c1\n  alerts:\n    - name: alert1\n")
+	_, err = storage.New(dir)
+	assert.Error(t, err)
+
+	// timestamps in history need to be in RFC3339 format
+	writeMockFile(t, dir, storage.UpgradeRisksFileName, "- cluster: c1\n  history:\n    - last_checked_at: yesterday\n")
+	_, err = storage.New(dir)
+	assert.Error(t, err)
+
+	// custom templates can be used too
+	writeMockFile(t, dir, storage.UpgradeRisksFileName, `
+- cluster: c1
+  console_url: https://console.example.com/
+  alerts:
+    - name: alert 1
+      url: ${CONSOLE_URL}/alerts/${ALERT_NAME}
+`)
+	s, err := storage.New(dir)
+	assert.NoError(t, err)
+	prediction, err := s.GetPredictionForCluster("c1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://console.example.com/alerts/alert+1", prediction.Predictors.Alerts[0].URL)
+}
+
+// TestRequestsLifecycle checks transitions of request ID states
+func TestRequestsLifecycle(t *testing.T) {
+	config := storage.RequestsConfiguration{
+		ReceivedDelay:   time.Second,
+		ProcessingDelay: time.Minute,
+	}
+	received := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, storage.RequestStatusReceived, storage.RequestStatusAt(config, received, received))
+	assert.Equal(t, storage.RequestStatusProcessing,
+		storage.RequestStatusAt(config, received, received.Add(time.Second)))
+	assert.Equal(t, storage.RequestStatusProcessed,
+		storage.RequestStatusAt(config, received, received.Add(time.Minute+time.Second)))
+}
+
+// TestRequests checks that initial request IDs are processed already and
+// that old request IDs expire
+func TestRequests(t *testing.T) {
+	const cluster = "34c3ecc5-624a-49a5-bab8-4fdc5e51a266"
+
+	s, err := storage.New(mockDataPath)
+	assert.NoError(t, err)
+
+	requests, found := s.ListOfRequestsForCluster(cluster)
+	assert.True(t, found)
+	assert.Len(t, requests, 12)
+	for i, request := range requests {
+		assert.Equal(t, storage.RequestStatusProcessed, request.Status)
+		assert.False(t, request.Processed.Before(request.Received))
+		if i > 0 {
+			assert.True(t, requests[i-1].Received.Before(request.Received))
+		}
+	}
+
+	request, found := s.GetRequestForCluster(cluster, "3nl2vda87ld6e3s25jlk7n2dna")
+	assert.True(t, found)
+	assert.Equal(t, storage.RequestStatusProcessed, request.Status)
+
+	request, found = s.GetRequestForCluster(cluster, "cccccccccccccccccccccccccc")
+	assert.True(t, found)
+	assert.Equal(t, storage.RequestStatusUnknown, request.Status)
+
+	_, found = s.ListOfRequestsForCluster("00000000-0000-0000-0000-000000000000")
+	assert.False(t, found)
+
+	// new request IDs expire, initial ones are kept
+	s.SetRequestsConfiguration(storage.RequestsConfiguration{Retention: time.Millisecond})
+	s.AddRequestForCluster(cluster, "dddddddddddddddddddddddddd", nil)
+	time.Sleep(10 * time.Millisecond)
+
+	requests, found = s.ListOfRequestsForCluster(cluster)
+	assert.True(t, found)
+	assert.Len(t, requests, 12)
+
+	request, found = s.GetRequestForCluster(cluster, "dddddddddddddddddddddddddd")
+	assert.True(t, found)
+	assert.Equal(t, storage.RequestStatusUnknown, request.Status)
+
+	request, found = s.GetRequestForCluster(cluster, "3nl2vda87ld6e3s25jlk7n2dna")
+	assert.True(t, found)
+	assert.Equal(t, storage.RequestStatusProcessed, request.Status)
+}
@@ -0,0 +1,309 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// Upgrade risks predictions for clusters. Predictions are read from fixture
+// file stored in mock data directory. Instead of prediction, the fixture can
+// simulate other outcomes of Upgrade Risks Prediction service, for example
+// unavailable service or cluster without data.
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// UpgradeRisksFileName is name of fixture file with upgrade risks
+// predictions that is expected to be stored in mock data directory. The file
+// is optional.
+const UpgradeRisksFileName = "upgrade_risks_predictions.yaml"
+
+// Templates of URLs to cluster console that are used for alerts and operator
+// conditions without URL set in fixture file. Templates set in fixture file
+// can use the same placeholders.
+const (
+	// AlertURLTemplate is the default template of URL to alert
+	AlertURLTemplate = "${CONSOLE_URL}/monitoring/alerts?orderBy=asc&sortBy=Severity&alert-name=${ALERT_NAME}"
+	// OperatorURLTemplate is the default template of URL to cluster operator
+	OperatorURLTemplate = "${CONSOLE_URL}/k8s/cluster/config.openshift.io~v1~ClusterOperator/${OPERATOR_NAME}"
+
+	consoleURLPlaceholder = "${CONSOLE_URL}"
+)
+
+// Outcomes of upgrade risks prediction that can be simulated for cluster
+const (
+	// OutcomePrediction returns prediction stored in fixture file
+	OutcomePrediction = "prediction"
+	// OutcomeManaged simulates managed cluster
+	OutcomeManaged = "managed"
+	// OutcomeNoAMS simulates unavailable AMS API
+	OutcomeNoAMS = "no_ams"
+	// OutcomeUnavailable simulates unavailable Upgrade Risks Prediction
+	// service
+	OutcomeUnavailable = "unavailable"
+	// OutcomeNoData simulates cluster without data
+	OutcomeNoData = "no_data"
+)
+
+var (
+	// ErrClusterManaged is returned for managed clusters that do not have
+	// upgrade risks prediction
+	ErrClusterManaged = errors.New("cluster is managed")
+
+	// ErrAMSUnavailable is returned when AMS API is not available
+	ErrAMSUnavailable = errors.New("AMS service unavailable")
+
+	// ErrPredictionUnavailable is returned when Upgrade Risks Prediction
+	// service is not available
+	ErrPredictionUnavailable = errors.New("upgrade risks prediction service unavailable")
+
+	// ErrNoPrediction is returned when there is no prediction for cluster
+	ErrNoPrediction = errors.New("no data for the cluster")
+)
+
+// UpgradeRiskEntry represents prediction or simulated outcome for one
+// cluster stored in fixture file. Recommended flag, console URL, alerts,
+// operator conditions and history are used for OutcomePrediction only, which
+// is the default one.
+type UpgradeRiskEntry struct {
+	Cluster            types.ClusterName         `yaml:"cluster"`
+	Outcome            string                    `yaml:"outcome"`
+	ConsoleURL         string                    `yaml:"console_url"`
+	Recommended        bool                      `yaml:"upgrade_recommended"`
+	Alerts             []types.Alert             `yaml:"alerts"`
+	OperatorConditions []types.OperatorCondition `yaml:"operator_conditions"`
+	History            []UpgradeRiskHistoryEntry `yaml:"history"`
+}
+
+// UpgradeRiskHistoryEntry represents one past prediction for cluster stored
+// in fixture file
+type UpgradeRiskHistoryEntry struct {
+	LastCheckedAt      string                    `yaml:"last_checked_at"`
+	Recommended        bool                      `yaml:"upgrade_recommended"`
+	Alerts             []types.Alert             `yaml:"alerts"`
+	OperatorConditions []types.OperatorCondition `yaml:"operator_conditions"`
+}
+
+// outcomeErrors maps simulated outcomes to errors returned from storage
+var outcomeErrors = map[string]error{
+	OutcomePrediction:  nil,
+	OutcomeManaged:     ErrClusterManaged,
+	OutcomeNoAMS:       ErrAMSUnavailable,
+	OutcomeUnavailable: ErrPredictionUnavailable,
+	OutcomeNoData:      ErrNoPrediction,
+}
+
+// readUpgradeRisks function reads and validates upgrade risks predictions
+// stored in given directory. No predictions are returned when the file does
+// not exist.
+func readUpgradeRisks(path string) (map[types.ClusterName]UpgradeRiskEntry, error) {
+	predictions := make(map[types.ClusterName]UpgradeRiskEntry)
+
+	fileName := filepath.Join(path, UpgradeRisksFileName)
+
+	var entries []UpgradeRiskEntry
+	err := readFixtureFile(path, UpgradeRisksFileName, &entries)
+	if err != nil {
+		return predictions, err
+	}
+
+	for _, entry := range entries {
+		if _, found := predictions[entry.Cluster]; found {
+			return predictions, fmt.Errorf("improper upgrade risks file %s: cluster %s defined twice",
+				fileName, entry.Cluster)
+		}
+		if entry.Outcome == "" {
+			entry.Outcome = OutcomePrediction
+		}
+		if _, found := outcomeErrors[entry.Outcome]; !found {
+			return predictions, fmt.Errorf("improper upgrade risks file %s: unknown outcome %s for cluster %s",
+				fileName, entry.Outcome, entry.Cluster)
+		}
+
+		err := expandURLs(&entry)
+		if err != nil {
+			return predictions, fmt.Errorf("improper upgrade risks file %s: cluster %s: %w",
+				fileName, entry.Cluster, err)
+		}
+		predictions[entry.Cluster] = entry
+	}
+
+	return predictions, nil
+}
+
+// expandURL replaces placeholders in URL template by console URL of cluster
+// and by given name
+func expandURL(template, consoleURL, placeholder, name string) (string, error) {
+	if strings.Contains(template, consoleURLPlaceholder) && consoleURL == "" {
+		return "", errors.New("console URL is not set")
+	}
+	return strings.NewReplacer(
+		consoleURLPlaceholder, consoleURL,
+		placeholder, name,
+	).Replace(template), nil
+}
+
+// expandURLs function expands URLs of all alerts and operator conditions
+// stored in given entry, including its history. Default templates are used
+// when URL is not set.
+func expandURLs(entry *UpgradeRiskEntry) error {
+	if entry.ConsoleURL != "" {
+		consoleURL, err := url.Parse(entry.ConsoleURL)
+		if err != nil || !consoleURL.IsAbs() {
+			return fmt.Errorf("improper console URL %s", entry.ConsoleURL)
+		}
+		entry.ConsoleURL = strings.TrimSuffix(entry.ConsoleURL, "/")
+	}
+
+	var err error
+	entry.Alerts, entry.OperatorConditions, err = expandPredictorURLs(
+		entry.ConsoleURL, entry.Alerts, entry.OperatorConditions)
+	if err != nil {
+		return err
+	}
+
+	for i := range entry.History {
+		item := &entry.History[i]
+		if err := checkTimestamp(item.LastCheckedAt); err != nil {
+			return fmt.Errorf("history: %w", err)
+		}
+		item.Alerts, item.OperatorConditions, err = expandPredictorURLs(
+			entry.ConsoleURL, item.Alerts, item.OperatorConditions)
+		if err != nil {
+			return fmt.Errorf("history %s: %w", item.LastCheckedAt, err)
+		}
+	}
+	return nil
+}
+
+// expandPredictorURLs function expands URLs of given alerts and operator
+// conditions
+func expandPredictorURLs(consoleURL string, alerts []types.Alert, conditions []types.OperatorCondition) (
+	[]types.Alert, []types.OperatorCondition, error,
+) {
+	// empty lists needs to be returned instead of nulls
+	expandedAlerts := make([]types.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		template := alert.URL
+		if template == "" {
+			template = AlertURLTemplate
+		}
+		expanded, err := expandURL(template, consoleURL,
+			"${ALERT_NAME}", url.QueryEscape(alert.Name))
+		if err != nil {
+			return nil, nil, fmt.Errorf("alert %s: %w", alert.Name, err)
+		}
+		alert.URL = expanded
+		expandedAlerts = append(expandedAlerts, alert)
+	}
+
+	expandedConditions := make([]types.OperatorCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		template := condition.URL
+		if template == "" {
+			template = OperatorURLTemplate
+		}
+		expanded, err := expandURL(template, consoleURL,
+			"${OPERATOR_NAME}", url.PathEscape(condition.Name))
+		if err != nil {
+			return nil, nil, fmt.Errorf("operator %s: %w", condition.Name, err)
+		}
+		condition.URL = expanded
+		expandedConditions = append(expandedConditions, condition)
+	}
+
+	return expandedAlerts, expandedConditions, nil
+}
+
+// predictionEntry returns prediction stored for the cluster. Error is
+// returned for simulated outcomes other than prediction and for clusters
+// without prediction.
+func (storage *MemoryStorage) predictionEntry(cluster types.ClusterName) (UpgradeRiskEntry, error) {
+	entry, found := storage.upgradeRisks[cluster]
+	if !found {
+		return entry, ErrNoPrediction
+	}
+	return entry, outcomeErrors[entry.Outcome]
+}
+
+// GetPredictionForCluster gets a prediction for the cluster. Error is
+// returned for simulated outcomes other than prediction and for clusters
+// without prediction.
+func (storage *MemoryStorage) GetPredictionForCluster(cluster types.ClusterName) (*types.UpgradeRiskPrediction, error) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	entry, err := storage.predictionEntry(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.UpgradeRiskPrediction{
+		Recommended: entry.Recommended,
+		Predictors: types.UpgradeRisksPredictors{
+			Alerts:             entry.Alerts,
+			OperatorConditions: entry.OperatorConditions,
+		},
+	}, nil
+}
+
+// GetPredictionHistoryForCluster gets past predictions for the cluster made
+// in given time range (both bounds included). Predictions are sorted by time
+// when they were made. Errors are the same as for GetPredictionForCluster.
+func (storage *MemoryStorage) GetPredictionHistoryForCluster(cluster types.ClusterName, from, to time.Time) (
+	[]types.UpgradeRiskPredictionHistoryItem, error,
+) {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	entry, err := storage.predictionEntry(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]types.UpgradeRiskPredictionHistoryItem, 0, len(entry.History))
+	for _, item := range entry.History {
+		// timestamps were checked when fixture file was read
+		checkedAt, _ := time.Parse(time.RFC3339, item.LastCheckedAt)
+		if checkedAt.Before(from) || checkedAt.After(to) {
+			continue
+		}
+		history = append(history, types.UpgradeRiskPredictionHistoryItem{
+			LastCheckedAt: item.LastCheckedAt,
+			UpgradeRiskPrediction: types.UpgradeRiskPrediction{
+				Recommended: item.Recommended,
+				Predictors: types.UpgradeRisksPredictors{
+					Alerts:             item.Alerts,
+					OperatorConditions: item.OperatorConditions,
+				},
+			},
+		})
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339, history[i].LastCheckedAt)
+		tj, _ := time.Parse(time.RFC3339, history[j].LastCheckedAt)
+		return ti.Before(tj)
+	})
+	return history, nil
+}
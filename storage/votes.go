@@ -0,0 +1,78 @@
+/*
+Copyright © 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// User votes (feedback) on rules hitting given cluster. Votes are not read
+// from mock data directory, so they are kept when mock data are reloaded.
+
+import (
+	"github.com/RedHatInsights/insights-results-aggregator-mock/types"
+)
+
+// voteKey identifies one vote
+type voteKey struct {
+	clusterName types.ClusterName
+	ruleID      types.RuleID
+	userID      types.UserID
+}
+
+// VoteOnRule stores user vote on rule for given cluster. Neutral vote
+// (UserVoteNone) removes the vote.
+func (storage *MemoryStorage) VoteOnRule(
+	clusterName types.ClusterName, ruleID types.RuleID, userID types.UserID, vote types.UserVote,
+) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	key := voteKey{clusterName, ruleID, userID}
+	if vote == types.UserVoteNone {
+		delete(storage.votes, key)
+		return
+	}
+
+	if storage.votes == nil {
+		storage.votes = make(map[voteKey]types.UserVote)
+	}
+	storage.votes[key] = vote
+}
+
+// GetUserVote returns user vote on rule for given cluster. UserVoteNone is
+// returned when user has not voted yet.
+func (storage *MemoryStorage) GetUserVote(
+	clusterName types.ClusterName, ruleID types.RuleID, userID types.UserID,
+) types.UserVote {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	return storage.votes[voteKey{clusterName, ruleID, userID}]
+}
+
+// GetUserVotesForCluster returns all user votes on rules for given cluster
+func (storage *MemoryStorage) GetUserVotesForCluster(
+	clusterName types.ClusterName, userID types.UserID,
+) map[types.RuleID]types.UserVote {
+	storage.mutex.RLock()
+	defer storage.mutex.RUnlock()
+
+	votes := make(map[types.RuleID]types.UserVote)
+	for key, vote := range storage.votes {
+		if key.clusterName == clusterName && key.userID == userID {
+			votes[key.ruleID] = vote
+		}
+	}
+	return votes
+}
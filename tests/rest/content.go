@@ -30,9 +30,9 @@ const contentEndpointPostfix = "content"
 
 // ContentAndGroups represents response from /content endpoint
 type ContentAndGroups struct {
-	Content []string `json:"content"`
-	Groups  []Group  `json:"groups"`
-	Status  string   `json:"status"`
+	Content []json.RawMessage `json:"content"`
+	Groups  []Group           `json:"groups"`
+	Status  string            `json:"status"`
 }
 
 // checkContentEndpoint check if the 'content' point (usually
@@ -35,7 +35,7 @@ type ListOfDVONamespaces struct {
 type DVOWorkloadItem struct {
 	Cluster   ClusterEntry   `json:"cluster"`
 	Namespace NamespaceEntry `json:"namespace"`
-	Reports   []Report       `json:"reports"`
+	Metadata  DVOMetadata    `json:"metadata"`
 }
 
 // ClusterEntry structure represents cluster info in namespaces/dvo payload
@@ -50,12 +50,15 @@ type NamespaceEntry struct {
 	Name string `json:"name"`
 }
 
-// Report structure represents one report in namespaces/dvo list of reports
-type Report struct {
-	Check       string `json:"check"`
-	Kind        string `json:"kind"`
-	Description string `json:"description"`
-	Remediation string `json:"remediation"`
+// DVOMetadata structure represents metadata of workload in namespaces/dvo
+// payload
+type DVOMetadata struct {
+	Recommendations int            `json:"recommendations"`
+	Objects         int            `json:"objects"`
+	ReportedAt      string         `json:"reported_at"`
+	LastCheckedAt   string         `json:"last_checked_at"`
+	HighestSeverity int            `json:"highest_severity"`
+	HitsBySeverity  map[string]int `json:"hits_by_severity"`
 }
 
 // dvoNamespacesEndpoint constructs an URL for list of all DVO namespaces
@@ -85,62 +88,167 @@ func checkListOfDVONamespaces() {
 		if response.Status != "ok" {
 			f.AddError("Status is not set to ok")
 		}
-		if len(response.Workloads) != 1 {
-			f.AddError("Just one workload is expected")
-		}
 
-		// check cluster entry
-		uuid := response.Workloads[0].Cluster.UUID
-		if uuid != "00000001-0001-0001-0001-000000000001" {
-			f.AddError("Improper cluster UUID: " + uuid)
+		// find workload for known cluster and namespace
+		var workload *DVOWorkloadItem
+		for i := range response.Workloads {
+			item := &response.Workloads[i]
+			if item.Cluster.UUID == "00000001-0001-0001-0001-000000000002" &&
+				item.Namespace.UUID == "fbcbe2d3-e398-4b40-9d5e-4eb46fe8286f" {
+				workload = item
+			}
 		}
-		displayName := response.Workloads[0].Cluster.DisplayName
-		if displayName != "Cluster #1" {
-			f.AddError("Improper cluster display name: " + displayName)
+		if workload == nil {
+			f.AddError("Workload for known cluster and namespace is not returned")
+		} else {
+			// display names and timestamps are read from fixture file
+			if workload.Cluster.DisplayName != "org1-dvo-cluster-02" {
+				f.AddError("Improper cluster display name: " + workload.Cluster.DisplayName)
+			}
+			if workload.Namespace.Name != "openshift-ingress" {
+				f.AddError("Improper namespace name: " + workload.Namespace.Name)
+			}
+			if workload.Metadata.ReportedAt != "2024-03-02T09:15:00Z" {
+				f.AddError("Improper reported_at timestamp: " + workload.Metadata.ReportedAt)
+			}
+			if workload.Metadata.LastCheckedAt != "2024-03-02T09:45:00Z" {
+				f.AddError("Improper last_checked_at timestamp: " + workload.Metadata.LastCheckedAt)
+			}
+			if workload.Metadata.Recommendations != 2 {
+				f.AddError(fmt.Sprintf("Two recommendations are expected, got %d", workload.Metadata.Recommendations))
+			}
 		}
+	}
+	f.PrintReport()
+}
+
+func checkListOfDVONamespacesOtherMethods() {
+	checkGetEndpointByOtherMethods(dvoNamespacesEndpoint(), false)
+}
+
+// DVONamespaceInfo structure represents response for
+// namespaces/dvo/{namespace_id}/info REST API endpoint
+type DVONamespaceInfo struct {
+	Status    string         `json:"status"`
+	Cluster   ClusterEntry   `json:"cluster"`
+	Namespace NamespaceEntry `json:"namespace"`
+}
+
+// dvoNamespaceInfoEndpoint constructs an URL for info about DVO namespace
+func dvoNamespaceInfoEndpoint(namespace string) string {
+	return fmt.Sprintf("%snamespaces/dvo/%s/info", apiURL, namespace)
+}
+
+func checkDVONamespaceInfo() {
+	const namespace = "fbcbe2d3-e398-4b40-9d5e-4eb46fe8286f"
+	url := dvoNamespaceInfoEndpoint(namespace)
+	f := frisby.Create("Check the 'namespaces/dvo/{namespace_id}/info' REST API point using HTTP GET method").Get(url)
+	f.Send()
+	f.ExpectStatus(http.StatusOK)
+	f.ExpectHeader(contentTypeHeader, ContentTypeJSON)
 
-		// check namespace entry
-		uuid = response.Workloads[0].Namespace.UUID
-		if uuid != "00000001-0001-0001-0001-000000000001" {
-			f.AddError("Improper namespace UUID: " + uuid)
+	// check the response
+	text, err := f.Resp.Content()
+	if err != nil {
+		f.AddError(err.Error())
+	} else {
+		response := DVONamespaceInfo{}
+		err := json.Unmarshal(text, &response)
+		if err != nil {
+			f.AddError(err.Error())
 		}
-		name := response.Workloads[0].Namespace.Name
-		if name != "Namespace #2" {
-			f.AddError("Improper namespace name: " + name)
+		if response.Status != "ok" {
+			f.AddError("Status is not set to ok")
 		}
-
-		// check reports
-		if len(response.Workloads[0].Reports) != 2 {
-			f.AddError("Two reports are expected")
+		if response.Cluster.UUID != "00000001-0001-0001-0001-000000000001" {
+			f.AddError("Improper cluster UUID: " + response.Cluster.UUID)
 		}
-
-		// test first report
-		report := response.Workloads[0].Reports[0]
-		expected := Report{
-			Check:       "no_anti_affinity",
-			Kind:        "Deployment",
-			Description: "Indicates when... ... ...",
-			Remediation: "Specify anti-affinity in your pod specification ... ... ...",
+		if response.Namespace.UUID != namespace {
+			f.AddError("Improper namespace UUID: " + response.Namespace.UUID)
+		}
+		if response.Cluster.DisplayName != "org1-dvo-cluster-01" {
+			f.AddError("Improper cluster display name: " + response.Cluster.DisplayName)
 		}
-		if report != expected {
-			f.AddError("First report is different from expected one")
+		if response.Namespace.Name != "openshift-ingress" {
+			f.AddError("Improper namespace name: " + response.Namespace.Name)
 		}
+	}
+	f.PrintReport()
+}
+
+func checkDVONamespaceInfoForUnknownNamespace() {
+	url := dvoNamespaceInfoEndpoint("00000000-0000-0000-0000-000000000000")
+	f := frisby.Create("Check the 'namespaces/dvo/{namespace_id}/info' REST API point for unknown namespace").Get(url)
+	f.Send()
+	f.ExpectStatus(http.StatusNotFound)
+	f.ExpectHeader(contentTypeHeader, ContentTypeJSON)
+	f.PrintReport()
+}
+
+// DVONamespaceReports structure represents response for
+// namespaces/dvo/{namespace_id}/reports REST API endpoint
+type DVONamespaceReports struct {
+	Status          string         `json:"status"`
+	Namespace       NamespaceEntry `json:"namespace"`
+	Recommendations []struct {
+		Check string `json:"check"`
+	} `json:"recommendations"`
+}
+
+// dvoNamespaceReportsEndpoint constructs an URL for list of recommendations
+// affecting DVO namespace
+func dvoNamespaceReportsEndpoint(namespace string) string {
+	return fmt.Sprintf("%snamespaces/dvo/%s/reports", apiURL, namespace)
+}
+
+func checkDVONamespaceReports(namespace string, expectedRecommendations int) {
+	url := dvoNamespaceReportsEndpoint(namespace)
+	f := frisby.Create("Check the 'namespaces/dvo/{namespace_id}/reports' REST API point using HTTP GET method").Get(url)
+	f.Send()
+	f.ExpectStatus(http.StatusOK)
+	f.ExpectHeader(contentTypeHeader, ContentTypeJSON)
 
-		// test second report
-		report = response.Workloads[0].Reports[1]
-		expected = Report{
-			Check:       "run_as_non_root",
-			Kind:        "Runtime",
-			Description: "Indicates when... ... ...",
-			Remediation: "Select different user to run this deployment... ... ...",
+	// check the response
+	text, err := f.Resp.Content()
+	if err != nil {
+		f.AddError(err.Error())
+	} else {
+		response := DVONamespaceReports{}
+		err := json.Unmarshal(text, &response)
+		if err != nil {
+			f.AddError(err.Error())
+		}
+		if response.Status != "ok" {
+			f.AddError("Status is not set to ok")
 		}
-		if report != expected {
-			f.AddError("First report is different from expected one")
+		if response.Namespace.UUID != namespace {
+			f.AddError("Improper namespace UUID: " + response.Namespace.UUID)
+		}
+		if len(response.Recommendations) != expectedRecommendations {
+			f.AddError(fmt.Sprintf("Expected %d recommendations, got %d",
+				expectedRecommendations, len(response.Recommendations)))
 		}
 	}
 	f.PrintReport()
 }
 
-func checkListOfDVONamespacesOtherMethods() {
-	checkGetEndpointByOtherMethods(dvoNamespacesEndpoint(), false)
+func checkDVONamespaceReportsNoRuleHit() {
+	checkDVONamespaceReports("2c4b9e1a-8f3d-4f6b-a7e5-0d9c3b1e6f42", 0)
+}
+
+func checkDVONamespaceReportsOneRuleHit() {
+	checkDVONamespaceReports("17a2e369-e96b-436e-924a-afa055280e44", 1)
+}
+
+func checkDVONamespaceReportsTwoRuleHits() {
+	checkDVONamespaceReports("a6c13355-60b6-42fb-9120-5819d9a0f5ad", 2)
+}
+
+func checkDVONamespaceReportsForUnknownNamespace() {
+	url := dvoNamespaceReportsEndpoint("00000000-0000-0000-0000-000000000000")
+	f := frisby.Create("Check the 'namespaces/dvo/{namespace_id}/reports' REST API point for unknown namespace").Get(url)
+	f.Send()
+	f.ExpectStatus(http.StatusNotFound)
+	f.ExpectHeader(contentTypeHeader, ContentTypeJSON)
+	f.PrintReport()
 }
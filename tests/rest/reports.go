@@ -43,7 +43,7 @@ type FullReportResponse struct {
 // given organization
 type AllReportsForOrganizationResponse struct {
 	Clusters    []string    `json:"clusters"`
-	Errors      []string    `json:"erors"`
+	Errors      []string    `json:"errors"`
 	Reports     interface{} `json:"reports"`
 	GeneratedAt time.Time   `json:"generated_at"` // timestamp
 }
@@ -270,9 +270,9 @@ func checkReportsForAllClustersInOrganizationPositiveTestCase() {
 			f.AddError(err.Error())
 		}
 		// parsing was ok, so check response content
-		// ATM no clusters and no errors should be returned from server
-		if len(response.Clusters) != 0 {
-			f.AddError("Expecting empty list of clusters")
+		// reports for all clusters from organization should be returned
+		if len(response.Clusters) == 0 {
+			f.AddError("Expecting non empty list of clusters")
 		}
 		if len(response.Errors) != 0 {
 			f.AddError("Expecting empty list of errors")
@@ -280,3 +280,14 @@ func checkReportsForAllClustersInOrganizationPositiveTestCase() {
 	}
 	f.PrintReport()
 }
+
+// checkReportsForAllClustersInOrganizationForbiddenTestCase check the REST
+// API endpoint to retrieve reports for all clusters in organization that can
+// not be accessed
+func checkReportsForAllClustersInOrganizationForbiddenTestCase() {
+	url := reportEndpointForAllReportsForOrg(organization2)
+	f := frisby.Create("Check the 'reports for all clusters' REST API point using HTTP GET method with forbidden organization").Get(url)
+	f.Send()
+	f.ExpectStatus(http.StatusForbidden)
+	f.PrintReport()
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/verdverm/frisby"
@@ -506,3 +507,87 @@ func checkRetrieveRequestReportForUnknownCluster() {
 	f.ExpectStatus(http.StatusNotFound)
 	f.PrintReport()
 }
+
+// GatheringResponse represents response returned when data gathering is
+// triggered
+type GatheringResponse struct {
+	Cluster       string `json:"cluster"`
+	RequestID     string `json:"requestID"`
+	RequestStatus string `json:"requestStatus"`
+	Status        string `json:"status"`
+}
+
+// gatheringEndpointForCluster helper function constructs URL for triggering
+// data gathering for given cluster
+func gatheringEndpointForCluster(clusterName string) string {
+	return fmt.Sprintf("%scluster/%s/gathering", apiURL, clusterName)
+}
+
+// checkTriggerGatheringForKnownCluster check the behavior of 'gathering' REST
+// API endpoint when known cluster is used
+func checkTriggerGatheringForKnownCluster() {
+	const clusterName = "00000001-624a-49a5-bab8-4fdc5e51a266"
+	url := gatheringEndpointForCluster(clusterName)
+
+	// construct request object
+	f := frisby.Create("Check the 'gathering' REST API point using HTTP POST method with known cluster").Post(url)
+	f.Send()
+	f.ExpectStatus(http.StatusAccepted)
+	f.ExpectHeader(contentTypeHeader, ContentTypeJSON)
+
+	// check the response
+	text, err := f.Resp.Content()
+	if err != nil {
+		f.AddError(err.Error())
+		f.PrintReport()
+		return
+	}
+	response := GatheringResponse{}
+	err = json.Unmarshal(text, &response)
+	if err != nil {
+		f.AddError(err.Error())
+	}
+	if response.Status != "ok" {
+		f.AddError(statusShouldBeSetToOK)
+	}
+	if response.Cluster != clusterName {
+		f.AddError(improperClusterNameReturned)
+	}
+	if !regexp.MustCompile(`^[0-9a-z]{26}$`).MatchString(response.RequestID) {
+		f.AddError(improperRequestID)
+	}
+	f.PrintReport()
+
+	// new request ID needs to be known
+	url = requestIDStatusEndpointForCluster(clusterName, response.RequestID)
+	f = frisby.Create("Check the 'request/status' REST API point using HTTP GET method with triggered request").Get(url)
+	f.Send()
+	f.ExpectStatus(http.StatusOK)
+
+	text, err = f.Resp.Content()
+	if err != nil {
+		f.AddError(err.Error())
+	} else {
+		status := RequestStatus{}
+		err := json.Unmarshal(text, &status)
+		if err != nil {
+			f.AddError(err.Error())
+		}
+		if status.Status == requestUnknown {
+			f.AddError(unexpectedStatus + status.Status)
+		}
+	}
+	f.PrintReport()
+}
+
+// checkTriggerGatheringForUnknownCluster check the behavior of 'gathering'
+// REST API endpoint when unknown cluster is used
+func checkTriggerGatheringForUnknownCluster() {
+	url := gatheringEndpointForCluster(unknownCluster)
+
+	// construct request object
+	f := frisby.Create("Check the 'gathering' REST API point using HTTP POST method with unknown cluster").Post(url)
+	f.Send()
+	f.ExpectStatus(http.StatusNotFound)
+	f.PrintReport()
+}
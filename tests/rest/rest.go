@@ -70,6 +70,7 @@ func BasicTests() {
 	checkWrongMethodsForClusterReportEndpoint()
 
 	checkReportsForAllClustersInOrganizationPositiveTestCase()
+	checkReportsForAllClustersInOrganizationForbiddenTestCase()
 
 	// implementations of these tests is stored in multiple_reports.go
 	checkMultipleReportsForKnownOrganizationAnd1KnownClusterUsingPostMethod()
@@ -97,9 +98,12 @@ func BasicTests() {
 	checkRetrieveRequestReportForKnownClusterAndKnownRequest()
 	checkRetrieveRequestReportForKnownClusterAndUnknownRequest()
 	checkRetrieveRequestReportForUnknownCluster()
+	checkTriggerGatheringForKnownCluster()
+	checkTriggerGatheringForUnknownCluster()
 
 	// implementations of these tests are stored in rules.go
 	checkRetrieveClusterDetailsForKnownRule()
+	checkRetrieveClusterDetailsIncludingAckedClusters()
 	checkRetrieveClusterDetailsForUnknownRule()
 	checkRetrieveClusterDetailsForImproperRule()
 
@@ -120,6 +124,12 @@ func BasicTests() {
 	// implementation of these tests are stored in dvo.go
 	checkListOfDVONamespaces()
 	checkListOfDVONamespacesOtherMethods()
+	checkDVONamespaceInfo()
+	checkDVONamespaceInfoForUnknownNamespace()
+	checkDVONamespaceReportsNoRuleHit()
+	checkDVONamespaceReportsOneRuleHit()
+	checkDVONamespaceReportsTwoRuleHits()
+	checkDVONamespaceReportsForUnknownNamespace()
 
 	// implementation of these tests are stored in upgrade_risk.go
 	checkUpgradeRiskEndpointWithClusterWithPositiveRiskPrediction()
@@ -128,4 +138,5 @@ func BasicTests() {
 	checkUpgradeRiskEndpointUnavailableServiceCase()
 	checkUpgradeRiskEndpointNotFoundCase()
 	checkUpgradeRiskEndpointForImproperClusterName()
+	checkUpgradeRiskHistoryEndpoint()
 }
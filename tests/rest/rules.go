@@ -30,6 +30,7 @@ import (
 type ClustersDetails struct {
 	MetaData ClustersDetailsMetadata `json:"meta"`
 	Clusters []string                `json:"data"`
+	Acked    []string                `json:"acked"`
 }
 
 // ClustersDetailsMetadata represents metadata about number of cluster hits
@@ -70,7 +71,9 @@ func checkRetrieveClusterDetailsForKnownRule() {
 		if err != nil {
 			f.AddError(err.Error())
 		}
-		if response.MetaData.Count != 24 {
+		// the rule is acked in organization 11789772 that owns four
+		// clusters hitting the rule
+		if response.MetaData.Count != 20 {
 			f.AddError("Improper metadata about number of clusters returned")
 		}
 		if response.MetaData.Component != component {
@@ -79,7 +82,7 @@ func checkRetrieveClusterDetailsForKnownRule() {
 		if response.MetaData.ErrorKey != errorKey {
 			f.AddError("Invalid error key")
 		}
-		if len(response.Clusters) != 24 {
+		if len(response.Clusters) != 20 {
 			f.AddError("Improper number of clusters returned")
 		}
 		// try just few clusters
@@ -90,6 +93,40 @@ func checkRetrieveClusterDetailsForKnownRule() {
 	f.PrintReport()
 }
 
+// checkRetrieveClusterDetailsIncludingAckedClusters checks if the
+// 'rule/{rule}/clusters_detail' point returns clusters with acked rule when
+// get_acked query parameter is used
+func checkRetrieveClusterDetailsIncludingAckedClusters() {
+	const component = "ccx_rules_ocp.external.rules.nodes_requirements_check.report"
+	const errorKey = "NODES_MINIMUM_REQUIREMENTS_NOT_MET"
+
+	url := clustersDetailsEndpointForRule(component, errorKey) + "?get_acked=true"
+	f := frisby.Create("Check the 'rule/{rule}/clusters_detail' REST API point using HTTP GET method (including acked clusters)").Get(url)
+	f.Send()
+	f.ExpectStatus(http.StatusOK)
+	f.ExpectHeader(contentTypeHeader, ContentTypeJSON)
+
+	// check the response
+	text, err := f.Resp.Content()
+	if err != nil {
+		f.AddError(err.Error())
+	} else {
+		response := ClustersDetails{}
+		err := json.Unmarshal(text, &response)
+		if err != nil {
+			f.AddError(err.Error())
+		}
+		if len(response.Clusters) != 24 {
+			f.AddError("Improper number of clusters returned")
+		}
+		if len(response.Acked) != 4 {
+			f.AddError("Improper number of clusters with acked rule returned")
+		}
+		testClusterIDExistence(f, response.Acked, "34c3ecc5-624a-49a5-bab8-4fdc5e51a266")
+	}
+	f.PrintReport()
+}
+
 // checkRetrieveClusterDetailsForUnknownRule checks if the
 // 'rule/clusters_detail' point responds correctly to HTTP GET command for
 // unknown rule
@@ -260,3 +260,57 @@ func checkUpgradeRiskEndpointForImproperClusterName() {
 
 	f.PrintReport()
 }
+
+// URPHistoryResponse structure represents response payload returned from URP
+// history endpoint
+type URPHistoryResponse struct {
+	Status             string                     `json:"status"`
+	URPRecommendations []URPHistoryRecommendation `json:"upgrade_recommendations"`
+}
+
+// URPHistoryRecommendation structure represents one past prediction returned
+// from URP history endpoint
+type URPHistoryRecommendation struct {
+	LastCheckedAt time.Time `json:"last_checked_at"`
+	URPRecommendations
+}
+
+// checkUpgradeRiskHistoryEndpoint check how/if URP history endpoint returns
+// past predictions for selected time range
+func checkUpgradeRiskHistoryEndpoint() {
+	url := constructURLUpgradeRiskEndpoint(clusterWithNegativeRisksPrediction) +
+		"/history?from=2024-03-02T00:00:00Z&to=2024-03-31T00:00:00Z"
+
+	// send request to the endpoint
+	f := frisby.Create("Check the endpoint to return upgrade risk predictions history for selected time range").Get(url)
+	f.Send()
+
+	// check the response from server
+	f.ExpectStatus(http.StatusOK)
+	f.ExpectHeader(contentTypeHeader, ContentTypeJSON)
+
+	// check the response payload
+	text, err := f.Resp.Content()
+	if err != nil {
+		f.AddError(err.Error())
+	} else {
+		response := URPHistoryResponse{}
+		err := json.Unmarshal(text, &response)
+		if err != nil {
+			f.AddError(err.Error())
+		}
+		if response.Status != "ok" {
+			f.AddError(statusShouldBeSetToOK)
+		}
+		if len(response.URPRecommendations) != 2 {
+			f.AddError("Exactly 2 past predictions are expected")
+		}
+		for i := 1; i < len(response.URPRecommendations); i++ {
+			if response.URPRecommendations[i].LastCheckedAt.Before(response.URPRecommendations[i-1].LastCheckedAt) {
+				f.AddError("Past predictions should be sorted by time")
+			}
+		}
+	}
+
+	f.PrintReport()
+}
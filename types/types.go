@@ -92,10 +92,11 @@ type RuleContentResponse struct {
 
 // DisabledRuleResponse represents a single disabled rule displaying only identifying information
 type DisabledRuleResponse struct {
-	RuleModule  string `json:"rule_id"`
-	Description string `json:"description"`
-	Generic     string `json:"details"`
-	DisabledAt  string `json:"disabled_at"`
+	RuleModule    string `json:"rule_id"`
+	Description   string `json:"description"`
+	Generic       string `json:"details"`
+	DisabledAt    string `json:"disabled_at"`
+	Justification string `json:"justification"`
 }
 
 // RuleID represents type for rule id
@@ -113,6 +114,13 @@ type ErrorKey string
 // UserID represents type for user id
 type UserID string
 
+// Identity represents identity of user that made the request
+type Identity struct {
+	AccountNumber string
+	OrgID         OrgID
+	UserID        UserID
+}
+
 // Rule represents the content of rule table
 type Rule struct {
 	Module     RuleID `json:"module"`
@@ -183,10 +191,21 @@ type AcknowledgementsMetadata struct {
 	Count int `json:"count"`
 }
 
+// AcknowledgementsLinks contains links to pages of list of
+// acknowledgements. Previous and next links are not set for the first and
+// the last page.
+type AcknowledgementsLinks struct {
+	First    string  `json:"first"`
+	Previous *string `json:"previous"`
+	Next     *string `json:"next"`
+	Last     string  `json:"last"`
+}
+
 // AcknowledgementsResponse is structure returned to client in JSON
 // serialization format
 type AcknowledgementsResponse struct {
 	Metadata AcknowledgementsMetadata `json:"meta"`
+	Links    AcknowledgementsLinks    `json:"links"`
 	Data     []Acknowledge            `json:"data"`
 }
 
@@ -234,6 +253,13 @@ type UpgradeRiskPrediction struct {
 	Predictors  UpgradeRisksPredictors `json:"upgrade_risks_predictors"`
 }
 
+// UpgradeRiskPredictionHistoryItem data structure represents one past
+// upgrade risks prediction together with time when it was made
+type UpgradeRiskPredictionHistoryItem struct {
+	LastCheckedAt string `json:"last_checked_at"`
+	UpgradeRiskPrediction
+}
+
 // ClusterUpgradeRiskPrediction data structure represents body of the reponse
 // for a multi-cluster upgrade-risk-prediction request
 type ClusterUpgradeRiskPrediction struct {
@@ -243,6 +269,17 @@ type ClusterUpgradeRiskPrediction struct {
 	Predictors  *UpgradeRisksPredictors `json:"upgrade_risks_predictors,omitempty"`
 }
 
+// RequestInfo structure represents state of one request ID used by On Demand
+// Data Gathering. Processed timestamp and rule hits are set for processed
+// requests only.
+type RequestInfo struct {
+	RequestID RequestID
+	Status    string
+	Received  time.Time
+	Processed time.Time
+	RuleHits  []SimplifiedRuleHit
+}
+
 // SimplifiedReport is structure returned by the service to IO to handle On Demand Data Gathering
 type SimplifiedReport struct {
 	Cluster   string              `json:"cluster"`